@@ -1,52 +1,136 @@
 package alog
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
+	"sync/atomic"
 
 	"github.com/mushroomsir/logger/pkg"
 )
 
-var defaultLogger = pkg.New(os.Stderr, pkg.Options{
+var defaultOptions = pkg.Options{
 	EnableFileLine: true,
 	EnableJSON:     true,
-	Skip:           4,
 	EnableGoID:     true,
-})
+}
+
+// defaultLogger is replaced atomically, so that ConfigureFromEnv and
+// SetDefault can run while other goroutines log.
+var defaultLogger atomic.Pointer[pkg.Logger]
+
+func init() {
+	defaultLogger.Store(pkg.New(os.Stderr, defaultOptions))
+}
+
+// ConfigureFromEnv replaces alog's default logger with one configured from
+// environment variables, see pkg.OptionsFromEnv. Variables which are not
+// set keep alog's defaults. It should be called before named loggers are
+// created, the default logger is left unchanged on error. It is safe to
+// call while other goroutines log.
+func ConfigureFromEnv(prefix string) error {
+	opt, err := pkg.OptionsFromEnv(prefix, defaultOptions)
+	if err != nil {
+		return err
+	}
+	defaultLogger.Store(pkg.New(os.Stderr, opt))
+	return nil
+}
+
+// SetDefault replaces alog's default logger and returns the previous one.
+// It is safe to call while other goroutines log.
+func SetDefault(l *pkg.Logger) (prev *pkg.Logger) {
+	return defaultLogger.Swap(l)
+}
 
 // SetLevel ...
 func SetLevel(level uint32) *pkg.Logger {
-	return defaultLogger.SetLevel(level)
+	return defaultLogger.Load().SetLevel(level)
 }
 
 // SetJSONLog set the logger writing JSON string log.
 func SetJSONLog() *pkg.Logger {
-	return defaultLogger.SetJSONLog()
+	return defaultLogger.Load().SetJSONLog()
 }
 
 // Level ...
 func Level() uint32 {
-	return defaultLogger.Level()
+	return defaultLogger.Load().Level()
+}
+
+// Root returns alog's default logger, the root of its tree of named
+// loggers, to manage the tree or to log through it like any pkg.Logger,
+// e.g. with Root().Every.
+func Root() *pkg.Logger {
+	return defaultLogger.Load()
+}
+
+// Named returns the named logger of alog's tree, see pkg.Logger.Named.
+// It belongs to the current default logger: after ConfigureFromEnv or
+// SetDefault, call Named again to log through the new one.
+func Named(name string) *pkg.Logger {
+	return defaultLogger.Load().Named(name)
+}
+
+// Loggers lists alog's named loggers and their effective levels.
+func Loggers() []pkg.LoggerLevel {
+	return defaultLogger.Load().Loggers()
+}
+
+// LevelHandler returns an http.Handler to read and change the levels of
+// alog's loggers at runtime, see pkg.Logger.LevelHandler.
+func LevelHandler() http.Handler {
+	return defaultLogger.Load().LevelHandler()
+}
+
+// NotifySignals lets SIGUSR1 and SIGUSR2 change alog's level, see
+// pkg.Logger.NotifySignals.
+func NotifySignals() (stop func()) {
+	return defaultLogger.Load().NotifySignals()
+}
+
+// SlogHandler returns a slog.Handler writing through alog's default logger.
+func SlogHandler() slog.Handler {
+	return pkg.NewSlogHandler(defaultLogger.Load())
+}
+
+// RedirectStdLog makes the standard library's default logger write records
+// at level through alog's default logger, see pkg.RedirectStdLog.
+func RedirectStdLog(level uint32) (restore func()) {
+	return pkg.RedirectStdLog(defaultLogger.Load(), level)
+}
+
+// SetVModule sets per-package or per-file levels, e.g. "db/*=debug,http/server.go=notice".
+func SetVModule(spec string) error {
+	return defaultLogger.Load().SetVModule(spec)
+}
+
+// SetRedaction masks sensitive values in alog's records, see pkg.Logger.SetRedaction.
+func SetRedaction(r *pkg.Redaction) error {
+	return defaultLogger.Load().SetRedaction(r)
 }
 
 // Debug ...
 func Debug(kv ...interface{}) {
-	defaultLogger.Debug(kv...)
+	defaultLogger.Load().Log(1, pkg.DebugLevel, kv...)
 
 }
 
 // Info ...
 func Info(kv ...interface{}) {
-	defaultLogger.Info(kv...)
+	defaultLogger.Load().Log(1, pkg.InfoLevel, kv...)
 }
 
 // Notice ...
 func Notice(kv ...interface{}) {
-	defaultLogger.Notice(kv...)
+	defaultLogger.Load().Log(1, pkg.NoticeLevel, kv...)
 }
 
 // Warning ...
 func Warning(kv ...interface{}) {
-	defaultLogger.Warning(kv...)
+	defaultLogger.Load().Log(1, pkg.WarningLevel, kv...)
 }
 
 // Check was deprecated please use NotNil
@@ -58,7 +142,7 @@ func Check(err interface{}, kv ...interface{}) bool {
 	for _, p := range kv {
 		l = append(l, p)
 	}
-	defaultLogger.Err(l...)
+	defaultLogger.Load().Log(1, pkg.ErrLevel, l...)
 	return true
 }
 
@@ -71,7 +155,7 @@ func IsNil(err interface{}, kv ...interface{}) bool {
 	for _, p := range kv {
 		l = append(l, p)
 	}
-	defaultLogger.Err(l...)
+	defaultLogger.Load().Log(1, pkg.ErrLevel, l...)
 	return false
 }
 
@@ -84,71 +168,117 @@ func NotNil(err interface{}, kv ...interface{}) bool {
 	for _, p := range kv {
 		l = append(l, p)
 	}
-	defaultLogger.Err(l...)
+	defaultLogger.Load().Log(1, pkg.ErrLevel, l...)
 	return true
 }
 
 // Err ...
 func Err(kv ...interface{}) {
-	defaultLogger.Err(kv...)
+	defaultLogger.Load().Log(1, pkg.ErrLevel, kv...)
 }
 
 // Crit ...
 func Crit(kv ...interface{}) {
-	defaultLogger.Crit(kv...)
+	defaultLogger.Load().Log(1, pkg.CritiLevel, kv...)
 }
 
 // Alert ...
 func Alert(kv ...interface{}) {
-	defaultLogger.Alert(kv...)
+	defaultLogger.Load().Log(1, pkg.AlertLevel, kv...)
 }
 
 // Emerg ...
 func Emerg(kv ...interface{}) {
-	defaultLogger.Emerg(kv...)
+	defaultLogger.Load().Log(1, pkg.EmergLevel, kv...)
 }
 
 // Debugf ...
 func Debugf(format string, args ...interface{}) {
-	defaultLogger.Debugf(format, args...)
+	defaultLogger.Load().Logf(1, pkg.DebugLevel, format, args...)
 }
 
 // Infof ...
 func Infof(format string, args ...interface{}) {
-	defaultLogger.Infof(format, args...)
+	defaultLogger.Load().Logf(1, pkg.InfoLevel, format, args...)
 }
 
 // Noticef ...
 func Noticef(format string, args ...interface{}) {
-	defaultLogger.Noticef(format, args...)
+	defaultLogger.Load().Logf(1, pkg.NoticeLevel, format, args...)
 }
 
 // Warningf ...
 func Warningf(format string, args ...interface{}) {
-	defaultLogger.Warningf(format, args...)
+	defaultLogger.Load().Logf(1, pkg.WarningLevel, format, args...)
 }
 
 // Errf ...
 func Errf(format string, args ...interface{}) {
-	defaultLogger.Errf(format, args...)
+	defaultLogger.Load().Logf(1, pkg.ErrLevel, format, args...)
 }
 
 // Critf ...
 func Critf(format string, args ...interface{}) {
-	defaultLogger.Critf(format, args...)
+	defaultLogger.Load().Logf(1, pkg.CritiLevel, format, args...)
 }
 
 // Alertf ...
 func Alertf(format string, args ...interface{}) {
-	defaultLogger.Alertf(format, args...)
+	defaultLogger.Load().Logf(1, pkg.AlertLevel, format, args...)
 }
 
 // Emergf ...
 func Emergf(format string, args ...interface{}) {
-	defaultLogger.Emergf(format, args...)
+	defaultLogger.Load().Logf(1, pkg.EmergLevel, format, args...)
 }
 
 // Panicf ...
 func Panicf(format string, args ...interface{}) {
-	defaultLogger.Panicf(format, args...)
+	defaultLogger.Load().Logf(1, pkg.EmergLevel, format, args...)
+	panic(fmt.Sprintf(format, args...))
+}
+
+// Debugt ...
+func Debugt(template string, args ...interface{}) {
+	defaultLogger.Load().Logt(1, pkg.DebugLevel, template, args...)
+}
+
+// Infot ...
+func Infot(template string, args ...interface{}) {
+	defaultLogger.Load().Logt(1, pkg.InfoLevel, template, args...)
+}
+
+// Noticet ...
+func Noticet(template string, args ...interface{}) {
+	defaultLogger.Load().Logt(1, pkg.NoticeLevel, template, args...)
+}
+
+// Warningt ...
+func Warningt(template string, args ...interface{}) {
+	defaultLogger.Load().Logt(1, pkg.WarningLevel, template, args...)
+}
+
+// Errt ...
+func Errt(template string, args ...interface{}) {
+	defaultLogger.Load().Logt(1, pkg.ErrLevel, template, args...)
+}
+
+// Critt ...
+func Critt(template string, args ...interface{}) {
+	defaultLogger.Load().Logt(1, pkg.CritiLevel, template, args...)
+}
+
+// Alertt ...
+func Alertt(template string, args ...interface{}) {
+	defaultLogger.Load().Logt(1, pkg.AlertLevel, template, args...)
+}
+
+// Emergt ...
+func Emergt(template string, args ...interface{}) {
+	defaultLogger.Load().Logt(1, pkg.EmergLevel, template, args...)
+}
+
+// StartCanonical ...
+func StartCanonical(ctx context.Context, kv ...interface{}) (context.Context, *pkg.CanonicalLine) {
+	return defaultLogger.Load().StartCanonical(ctx, kv...)
 }
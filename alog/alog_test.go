@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/mushroomsir/logger/pkg"
@@ -22,12 +23,11 @@ func TestAlog(t *testing.T) {
 	require := require.New(t)
 
 	buf := new(bytes.Buffer)
-	defaultLogger = pkg.New(buf, pkg.Options{
+	defaultLogger.Store(pkg.New(buf, pkg.Options{
 		EnableJSON:     true,
 		EnableFileLine: true,
-		Skip:           4,
-	})
-	defaultLogger.SetLevel(pkg.DebugLevel)
+	}))
+	defaultLogger.Load().SetLevel(pkg.DebugLevel)
 
 	cases := []struct {
 		fun   func(v ...interface{})
@@ -78,8 +78,8 @@ func TestAlog(t *testing.T) {
 	require.Contains(buf.String(), `"Userid":"123456"`)
 	buf.Reset()
 
-	defaultLogger.SetJSONLog()
-	defaultLogger.Info("a", "1")
+	defaultLogger.Load().SetJSONLog()
+	defaultLogger.Load().Info("a", "1")
 
 	v := map[string]string{}
 	err := json.Unmarshal(buf.Bytes(), &v)
@@ -89,3 +89,84 @@ func TestAlog(t *testing.T) {
 	require.NotEmpty(v["timestamp"])
 	require.NotEmpty(v["file"])
 }
+
+func TestNamed(t *testing.T) {
+	require := require.New(t)
+
+	buf := new(bytes.Buffer)
+	defaultLogger.Store(pkg.New(buf, pkg.Options{
+		EnableJSON:     true,
+		EnableFileLine: true,
+	}))
+	require.Equal(defaultLogger.Load(), Root())
+
+	stripe := Named("payments").Named("stripe")
+	require.Equal(stripe, Named("payments.stripe"))
+	stripe.Info("a", 1)
+	require.Contains(buf.String(), `INFO {"a":1,"file":"logger/alog/alog_test.go:105","logger":"payments.stripe"}`)
+	buf.Reset()
+
+	SetLevel(pkg.DebugLevel)
+	stripe.Debug("a", 1)
+	require.Contains(buf.String(), "DEBUG")
+	require.Equal([]pkg.LoggerLevel{
+		{Name: "", Level: pkg.DebugLevel},
+		{Name: "payments", Level: pkg.DebugLevel, Inherited: true},
+		{Name: "payments.stripe", Level: pkg.DebugLevel, Inherited: true},
+	}, Loggers())
+}
+
+func TestAlogTemplate(t *testing.T) {
+	require := require.New(t)
+
+	buf := new(bytes.Buffer)
+	defaultLogger.Store(pkg.New(buf, pkg.Options{
+		EnableJSON:     true,
+		EnableFileLine: true,
+	}))
+	defaultLogger.Load().SetLevel(pkg.DebugLevel)
+
+	cases := []struct {
+		fun   func(template string, args ...interface{})
+		level string
+	}{
+		{Debugt, "DEBUG"},
+		{Infot, "INFO"},
+		{Noticet, "NOTICE"},
+		{Warningt, "WARNING"},
+		{Errt, "ERR"},
+		{Critt, "CRIT"},
+		{Alertt, "ALERT"},
+		{Emergt, "EMERG"},
+	}
+	for _, c := range cases {
+		c.fun("user {User}", "u1")
+		require.Contains(buf.String(), c.level+` {"User":"u1","file":"logger/alog/alog_test.go:`)
+		require.Contains(buf.String(), `"message":"user u1","template":"user {User}"}`)
+		buf.Reset()
+	}
+}
+
+func TestRoot(t *testing.T) {
+	require := require.New(t)
+
+	buf := new(bytes.Buffer)
+	defaultLogger.Store(pkg.New(buf, pkg.Options{
+		EnableJSON:     true,
+		EnableFileLine: true,
+	}))
+	Root().Info("a", 1)
+	require.Contains(buf.String(), `"file":"logger/alog/alog_test.go:158"`)
+	buf.Reset()
+
+	for i := 0; i < 3; i++ {
+		Root().FirstN(1).Info("i", i)
+	}
+	require.Equal(1, strings.Count(buf.String(), "INFO"))
+	buf.Reset()
+
+	db := Named("db")
+	SetJSONLog()
+	db.Info("a", 1)
+	require.Regexp(`^\{"a":1,"file":"logger/alog/alog_test.go:\d+","level":"INFO","logger":"db"`, buf.String())
+}
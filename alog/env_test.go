@@ -0,0 +1,24 @@
+package alog
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mushroomsir/logger/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigureFromEnv(t *testing.T) {
+	require := require.New(t)
+	defer SetDefault(Root())
+
+	os.Setenv("TEST_ALOG_LEVEL", "nope")
+	require.EqualError(ConfigureFromEnv("TEST_ALOG"), `TEST_ALOG_LEVEL: invalid level "nope"`)
+	os.Setenv("TEST_ALOG_LEVEL", "err")
+	os.Setenv("TEST_ALOG_OUTPUT", "stdout")
+	defer os.Unsetenv("TEST_ALOG_LEVEL")
+	defer os.Unsetenv("TEST_ALOG_OUTPUT")
+	require.Nil(ConfigureFromEnv("TEST_ALOG"))
+	require.Equal(pkg.ErrLevel, Level())
+	require.Equal(os.Stdout, Root().Out)
+}
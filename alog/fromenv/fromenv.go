@@ -0,0 +1,16 @@
+// Package fromenv configures alog's default logger from the LOG_LEVEL,
+// LOG_FORMAT, LOG_TIME_FORMAT, LOG_FILE_LINE, LOG_GOID and LOG_OUTPUT
+// environment variables when imported:
+//
+//	import _ "github.com/mushroomsir/logger/alog/fromenv"
+//
+// Invalid values are reported as an ERR record and alog keeps its defaults.
+package fromenv
+
+import "github.com/mushroomsir/logger/alog"
+
+func init() {
+	if err := alog.ConfigureFromEnv("LOG"); err != nil {
+		alog.Err("error", err)
+	}
+}
@@ -0,0 +1,217 @@
+// Package config builds pkg.Logger trees from JSON or YAML files and
+// applies changes of those files to running loggers.
+//
+//	level: info
+//	format: json
+//	sinks: [stderr, /var/log/app.log]
+//	packages: ["db/*=debug", "http/server.go=notice"]
+//	fields: {service: api}
+//	loggers:
+//	  payments.stripe:
+//	    level: debug
+//	    fields: {team: billing}
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mushroomsir/logger/pkg"
+	"gopkg.in/yaml.v3"
+)
+
+// Config describes a root logger and its named loggers.
+type Config struct {
+	Level      string                 `json:"level" yaml:"level"`
+	Format     string                 `json:"format" yaml:"format"`
+	TimeFormat string                 `json:"time_format" yaml:"time_format"`
+	FileLine   *bool                  `json:"file_line" yaml:"file_line"`
+	GoID       bool                   `json:"goid" yaml:"goid"`
+	Sinks      []string               `json:"sinks" yaml:"sinks"`
+	Packages   []string               `json:"packages" yaml:"packages"`
+	Fields     map[string]interface{} `json:"fields" yaml:"fields"`
+	Loggers    map[string]Logger      `json:"loggers" yaml:"loggers"`
+}
+
+// Logger describes a named logger, an empty level is inherited.
+type Logger struct {
+	Level  string                 `json:"level" yaml:"level"`
+	Fields map[string]interface{} `json:"fields" yaml:"fields"`
+}
+
+// Load reads the config file at path, as YAML if its extension is .yaml or
+// .yml and as JSON otherwise.
+func Load(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, formatOf(path))
+}
+
+func formatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+	return "json"
+}
+
+// Parse decodes and validates a config, format is "json" or "yaml".
+func Parse(data []byte, format string) (*Config, error) {
+	c := &Config{}
+	var err error
+	switch format {
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(c)
+	case "yaml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err = dec.Decode(c); err == io.EOF {
+			err = nil
+		}
+	default:
+		return nil, fmt.Errorf("config: unknown format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: %v", err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Validate reports the first invalid value of c.
+func (c *Config) Validate() error {
+	if c.Level != "" {
+		if _, err := pkg.LookupLevel(c.Level); err != nil {
+			return fmt.Errorf("config: %v", err)
+		}
+	}
+	switch strings.ToLower(c.Format) {
+	case "", "text", "json", "logfmt", "console":
+	default:
+		return fmt.Errorf("config: invalid format %q, want text, json, logfmt or console", c.Format)
+	}
+	if err := pkg.ValidateVModule(c.vmodule()); err != nil {
+		return fmt.Errorf("config: %v", err)
+	}
+	for name, l := range c.Loggers {
+		if strings.Trim(name, ".") == "" {
+			return fmt.Errorf("config: invalid logger name %q", name)
+		}
+		if l.Level != "" {
+			if _, err := pkg.LookupLevel(l.Level); err != nil {
+				return fmt.Errorf("config: logger %q: %v", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Config) vmodule() string {
+	return strings.Join(c.Packages, ",")
+}
+
+// Build returns a root logger and its named loggers described by c. Files
+// listed in sinks are opened for appending and stay open, records are
+// written to stderr if there is no sink.
+func (c *Config) Build() (*pkg.Logger, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	var sinks []io.Writer
+	for _, s := range c.Sinks {
+		switch strings.ToLower(s) {
+		case "stderr":
+			sinks = append(sinks, os.Stderr)
+		case "stdout":
+			sinks = append(sinks, os.Stdout)
+		default:
+			f, err := os.OpenFile(s, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+			if err != nil {
+				return nil, fmt.Errorf("config: %v", err)
+			}
+			sinks = append(sinks, f)
+		}
+	}
+	out := io.Writer(os.Stderr)
+	if len(sinks) == 1 {
+		out = sinks[0]
+	} else if len(sinks) > 1 {
+		out = io.MultiWriter(sinks...)
+	}
+	root := pkg.New(out, pkg.Options{
+		EnableJSON:     true,
+		EnableFileLine: c.FileLine == nil || *c.FileLine,
+		EnableGoID:     c.GoID,
+		TimeFormat:     c.TimeFormat,
+		Format:         c.Format,
+	})
+	return root, c.apply(root, nil)
+}
+
+// Apply sets the level, package levels and fields of c on root and its
+// named loggers. Nothing is changed if c is invalid.
+func (c *Config) Apply(root *pkg.Logger) error {
+	return c.apply(root, nil)
+}
+
+// apply is Apply resetting the named loggers of prev which c no longer lists.
+func (c *Config) apply(root *pkg.Logger, prev *Config) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if err := root.SetVModule(c.vmodule()); err != nil {
+		return fmt.Errorf("config: %v", err)
+	}
+	if c.Level != "" {
+		level, _ := pkg.LookupLevel(c.Level)
+		root.SetLevel(level)
+	} else {
+		root.ResetLevel()
+	}
+	root.SetFields(fieldsKV(c.Fields)...)
+	if prev != nil {
+		for name := range prev.Loggers {
+			if _, ok := c.Loggers[name]; !ok {
+				root.Named(name).ResetLevel().SetFields()
+			}
+		}
+	}
+	for name, l := range c.Loggers {
+		named := root.Named(name)
+		if l.Level != "" {
+			level, _ := pkg.LookupLevel(l.Level)
+			named.SetLevel(level)
+		} else {
+			named.ResetLevel()
+		}
+		named.SetFields(fieldsKV(l.Fields)...)
+	}
+	return nil
+}
+
+// fieldsKV returns the fields as key value pairs sorted by key.
+func fieldsKV(fields map[string]interface{}) []interface{} {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kv := make([]interface{}, 0, 2*len(keys))
+	for _, k := range keys {
+		kv = append(kv, k, fields[k])
+	}
+	return kv
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/mushroomsir/logger/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+const yamlConfig = `
+level: warning
+format: json
+sinks: [%s]
+packages: ["db/*=debug"]
+fields: {service: api, version: 2}
+loggers:
+  payments.stripe:
+    level: debug
+    fields: {team: billing}
+`
+
+func TestParse(t *testing.T) {
+	require := require.New(t)
+
+	c, err := Parse([]byte(`{"level":"err","packages":["db/*=7"],"loggers":{"a":{"fields":{"x":1}}}}`), "json")
+	require.Nil(err)
+	require.Equal("err", c.Level)
+	require.Equal("db/*=7", c.vmodule())
+	require.Equal(map[string]interface{}{"x": float64(1)}, c.Loggers["a"].Fields)
+
+	c, err = Parse([]byte(""), "yaml")
+	require.Nil(err)
+	require.Equal(&Config{}, c)
+
+	for _, e := range []struct{ data, format, err string }{
+		{`{"level":"loud"}`, "json", `config: invalid level "loud"`},
+		{`{"lvl":"err"}`, "json", `config: json: unknown field "lvl"`},
+		{`format: xml`, "yaml", `config: invalid format "xml", want text, json, logfmt or console`},
+		{`packages: ["db/*"]`, "yaml", `config: vmodule: invalid rule "db/*", want pattern=level`},
+		{`loggers: {a: {level: loud}}`, "yaml", `config: logger "a": invalid level "loud"`},
+		{`loggers: {".": {}}`, "yaml", `config: invalid logger name "."`},
+		{`level: [`, "yaml", "config: yaml: line 1: did not find expected node content"},
+		{`level = err`, "toml", `config: unknown format "toml"`},
+	} {
+		_, err = Parse([]byte(e.data), e.format)
+		require.EqualError(err, e.err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.yaml")
+	out := filepath.Join(dir, "app.log")
+	require.Nil(ioutil.WriteFile(path, []byte(fmt.Sprintf(yamlConfig, out)), 0644))
+
+	c, err := Load(path)
+	require.Nil(err)
+	root, err := c.Build()
+	require.Nil(err)
+	require.Equal(pkg.WarningLevel, root.Level())
+	require.Equal("db/*=debug", root.VModule())
+	require.Equal([]interface{}{"service", "api", "version", 2}, root.Fields())
+	stripe := root.Lookup("payments.stripe")
+	require.NotNil(stripe)
+	require.Equal(pkg.DebugLevel, stripe.Level())
+
+	stripe.Debug("a", 1)
+	root.Info("a", 1)
+	data, err := ioutil.ReadFile(out)
+	require.Nil(err)
+	require.Equal(1, bytes.Count(data, []byte("\n")))
+	require.Contains(string(data), `"a":1,"file":"logger/config/config_test.go:72","level":"DEBUG","logger":"payments.stripe","service":"api","team":"billing"`)
+
+	_, err = (&Config{Sinks: []string{filepath.Join(out, "x")}}).Build()
+	require.NotNil(err)
+	_, err = (&Config{Level: "loud"}).Build()
+	require.NotNil(err)
+	root, err = (&Config{Sinks: []string{"stdout", "stderr"}}).Build()
+	require.Nil(err)
+	require.Equal(pkg.InfoLevel, root.Level())
+
+	require.NotNil((&Config{Level: "loud"}).Apply(root))
+	require.Nil((&Config{Level: "debug"}).Apply(root))
+	require.Equal(pkg.DebugLevel, root.Level())
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/mushroomsir/logger/pkg"
+)
+
+// WatchOptions ...
+type WatchOptions struct {
+	// Interval between two checks of the file, one second if zero.
+	Interval time.Duration
+	// OnReload is called with every config applied after a change.
+	OnReload func(*Config)
+	// OnError is called with every rejected reload, which is written to
+	// the root logger as an ERR record if OnError is nil.
+	OnError func(error)
+}
+
+// Watcher applies the changes of a config file to a running logger.
+type Watcher struct {
+	path string
+	root *pkg.Logger
+	opt  WatchOptions
+
+	mu      sync.Mutex
+	current *Config
+	data    []byte
+	modTime time.Time
+
+	done   chan struct{}
+	exited chan struct{}
+	once   sync.Once
+}
+
+// Watch polls the config file at path, expected to be the one root was
+// built from, and applies the level, package level and field changes to
+// root and its named loggers without replacing them. A reload which does
+// not parse or validate is rejected and the previous config stays in
+// effect. Changes of the format, sinks and other output settings need a
+// restart, they are reported and the rest of the config is applied.
+func Watch(path string, root *pkg.Logger, options ...WatchOptions) (*Watcher, error) {
+	w := &Watcher{
+		path:   path,
+		root:   root,
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+	if len(options) > 0 {
+		w.opt = options[0]
+	}
+	if w.opt.Interval <= 0 {
+		w.opt.Interval = time.Second
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	w.modTime = info.ModTime()
+	if w.data, err = ioutil.ReadFile(path); err != nil {
+		return nil, err
+	}
+	if w.current, err = Parse(w.data, formatOf(path)); err != nil {
+		return nil, err
+	}
+	go w.run()
+	return w, nil
+}
+
+// Config returns the config in effect.
+func (w *Watcher) Config() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// Close stops watching the file.
+func (w *Watcher) Close() error {
+	w.once.Do(func() {
+		close(w.done)
+		<-w.exited
+	})
+	return nil
+}
+
+func (w *Watcher) run() {
+	defer close(w.exited)
+	ticker := time.NewTicker(w.opt.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			if err := w.check(); err != nil {
+				w.report(err)
+			}
+		}
+	}
+}
+
+// check reloads the file if it changed since the last check.
+func (w *Watcher) check() error {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return fmt.Errorf("config: %v, reload rejected", err)
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == int64(len(w.data)) {
+		return nil
+	}
+	data, err := ioutil.ReadFile(w.path)
+	if err != nil {
+		return fmt.Errorf("config: %v, reload rejected", err)
+	}
+	w.modTime = info.ModTime()
+	if bytes.Equal(data, w.data) {
+		return nil
+	}
+	w.data = data
+	c, err := Parse(data, formatOf(w.path))
+	if err == nil {
+		err = c.apply(w.root, w.Config())
+	}
+	if err != nil {
+		return fmt.Errorf("%v, reload rejected", err)
+	}
+	w.mu.Lock()
+	prev := w.current
+	w.current = c
+	w.mu.Unlock()
+	if w.opt.OnReload != nil {
+		w.opt.OnReload(c)
+	}
+	if c.Format != prev.Format || c.TimeFormat != prev.TimeFormat || c.GoID != prev.GoID ||
+		!reflect.DeepEqual(c.FileLine, prev.FileLine) || !reflect.DeepEqual(c.Sinks, prev.Sinks) {
+		return fmt.Errorf("config: format, time_format, file_line, goid and sinks changes need a restart")
+	}
+	return nil
+}
+
+func (w *Watcher) report(err error) {
+	if w.opt.OnError != nil {
+		w.opt.OnError(err)
+		return
+	}
+	w.root.Err("error", err, "path", w.path)
+}
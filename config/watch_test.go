@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mushroomsir/logger/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.yaml")
+	out := filepath.Join(dir, "app.log")
+	write := func(data string, age time.Duration) {
+		require.Nil(ioutil.WriteFile(path, []byte(data), 0644))
+		mtime := time.Now().Add(-age)
+		require.Nil(os.Chtimes(path, mtime, mtime))
+	}
+	write(fmt.Sprintf(yamlConfig, out), time.Hour)
+
+	c, err := Load(path)
+	require.Nil(err)
+	root, err := c.Build()
+	require.Nil(err)
+	stripe := root.Lookup("payments.stripe")
+
+	reloads := make(chan *Config, 10)
+	errs := make(chan error, 10)
+	w, err := Watch(path, root, WatchOptions{
+		Interval: 5 * time.Millisecond,
+		OnReload: func(c *Config) { reloads <- c },
+		OnError: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		},
+	})
+	require.Nil(err)
+	defer w.Close()
+	require.Equal(c, w.Config())
+
+	write(fmt.Sprintf(`
+level: err
+format: json
+sinks: [%s]
+fields: {service: api}
+loggers:
+  payments:
+    level: notice
+`, out), 30*time.Minute)
+	c = <-reloads
+	require.Equal(c, w.Config())
+	require.Equal(pkg.ErrLevel, root.Level())
+	require.Equal("", root.VModule())
+	require.Equal([]interface{}{"service", "api"}, root.Fields())
+	require.Equal(pkg.NoticeLevel, stripe.Level())
+	require.Empty(stripe.Fields())
+
+	write("level: loud\n", 20*time.Minute)
+	require.EqualError(<-errs, `config: invalid level "loud", reload rejected`)
+	require.Equal(c, w.Config())
+	require.Equal(pkg.ErrLevel, root.Level())
+
+	write("level: info\nformat: logfmt\n", 10*time.Minute)
+	<-reloads
+	require.EqualError(<-errs, "config: format, time_format, file_line, goid and sinks changes need a restart")
+	require.Equal(pkg.InfoLevel, root.Level())
+
+	require.Nil(os.Remove(path))
+	require.Contains((<-errs).Error(), "no such file or directory, reload rejected")
+	require.Nil(w.Close())
+	require.Nil(w.Close())
+
+	_, err = Watch(path, root)
+	require.NotNil(err)
+	write("level: loud\n", 0)
+	_, err = Watch(path, root)
+	require.NotNil(err)
+}
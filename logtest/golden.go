@@ -0,0 +1,56 @@
+package logtest
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mushroomsir/logger/pkg"
+)
+
+var update = flag.Bool("logtest.update", false, "update the golden files of logtest.Golden")
+
+// NewDeterministic returns a logger writing deterministic records to w,
+// see pkg.Options.Deterministic, at DebugLevel with file and line by
+// default.
+func NewDeterministic(w io.Writer, options ...pkg.Options) *pkg.Logger {
+	opt := pkg.Options{EnableJSON: true, EnableFileLine: true}
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if opt.Level == "" {
+		opt.Level = "debug"
+	}
+	opt.Deterministic = true
+	return pkg.New(w, opt)
+}
+
+// Golden compares got with the file testdata/<name>.golden and reports an
+// error if they differ. Running the tests with -logtest.update writes got
+// to the file instead.
+func Golden(t TB, name string, got []byte) bool {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("logtest: %v", err)
+		}
+		if err := ioutil.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("logtest: %v", err)
+		}
+		return true
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Errorf("logtest: %v, run the tests with -logtest.update to create it", err)
+		return false
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("logtest: output differs from %s, run the tests with -logtest.update to update it\ngot:\n%s\nwant:\n%s", path, got, want)
+		return false
+	}
+	return true
+}
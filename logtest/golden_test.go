@@ -0,0 +1,39 @@
+package logtest
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/mushroomsir/logger/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGolden(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := NewDeterministic(buf, pkg.Options{EnableJSON: true, EnableGoID: true, Level: "debug"})
+	logger.Info("message", "started", "zone", "eu", "attempt", 1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Named("worker").Err("message", "failed", "error", errors.New("timeout"))
+	}()
+	wg.Wait()
+	logger.Debugf("%d done", 2)
+	require.True(Golden(t, "deterministic", buf.Bytes()))
+	if *update {
+		return
+	}
+
+	fake := &fakeTB{}
+	require.False(Golden(fake, "deterministic", []byte("other\n")))
+	require.Len(fake.errors, 1)
+	require.Contains(fake.errors[0], "logtest: output differs from testdata/deterministic.golden")
+	require.False(Golden(fake, "missing", buf.Bytes()))
+	require.Len(fake.errors, 2)
+	require.Contains(fake.errors[1], "run the tests with -logtest.update to create it")
+	require.Empty(fake.fatals)
+}
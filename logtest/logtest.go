@@ -0,0 +1,251 @@
+// Package logtest provides loggers for tests: New writes records to the
+// test log, NewObserver captures them as entries to assert on.
+//
+//	logger, logs := logtest.NewObserver()
+//	svc := NewService(logger)
+//	svc.Charge(user, 10)
+//	logs.FilterLevel(pkg.ErrLevel).AssertCount(t, 0)
+//	logs.FilterMessage("charged").FilterField("user", user.ID).AssertCount(t, 1)
+//
+// Alog swaps an observed logger in as alog's default logger for the time
+// of a test.
+package logtest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mushroomsir/logger/alog"
+	"github.com/mushroomsir/logger/pkg"
+)
+
+// New returns a logger writing its records to t.Log, at DebugLevel with
+// file and line by default. Records written after the test finished are
+// dropped.
+func New(t testing.TB, options ...pkg.Options) *pkg.Logger {
+	opt := pkg.Options{EnableJSON: true, EnableFileLine: true}
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if opt.Level == "" {
+		opt.Level = "debug"
+	}
+	w := &testWriter{t: t}
+	t.Cleanup(w.finish)
+	return pkg.New(w, opt)
+}
+
+type testWriter struct {
+	t        testing.TB
+	mu       sync.Mutex
+	finished bool
+}
+
+func (w *testWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if s := strings.TrimSuffix(string(p), "\n"); s != "" && !w.finished {
+		w.t.Log(s)
+	}
+	return len(p), nil
+}
+
+func (w *testWriter) finish() {
+	w.mu.Lock()
+	w.finished = true
+	w.mu.Unlock()
+}
+
+// Alog replaces alog's default logger with an observed one until the test
+// finishes and returns its observer.
+func Alog(t testing.TB) *Observer {
+	logger, o := NewObserver(pkg.Options{EnableJSON: true, EnableFileLine: true})
+	prev := alog.SetDefault(logger)
+	t.Cleanup(func() { alog.SetDefault(prev) })
+	return o
+}
+
+// Entry is a captured record. Field values are those kept by slog, e.g.
+// int64 for all signed integers, nested maps are map[string]interface{}.
+type Entry struct {
+	Time    time.Time
+	Level   uint32
+	Logger  string
+	Caller  string
+	Message string
+	Fields  map[string]interface{}
+}
+
+// String ...
+func (e Entry) String() string {
+	return fmt.Sprintf("%s %q %v", pkg.LevelName(e.Level), e.Message, e.Fields)
+}
+
+// Observer captures the records of a logger.
+type Observer struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewObserver returns a logger, at DebugLevel with file and line by
+// default, whose records are captured by the returned observer instead of
+// being written.
+func NewObserver(options ...pkg.Options) (*pkg.Logger, *Observer) {
+	opt := pkg.Options{EnableJSON: true, EnableFileLine: true}
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	o := &Observer{}
+	return pkg.NewSlogLogger(observerHandler{o}, opt), o
+}
+
+// Entries returns a copy of the captured entries.
+func (o *Observer) Entries() []Entry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]Entry(nil), o.entries...)
+}
+
+// Len returns the number of captured entries.
+func (o *Observer) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
+
+// Messages returns the messages of the captured entries.
+func (o *Observer) Messages() []string {
+	entries := o.Entries()
+	messages := make([]string, len(entries))
+	for i, e := range entries {
+		messages[i] = e.Message
+	}
+	return messages
+}
+
+// Reset drops the captured entries.
+func (o *Observer) Reset() {
+	o.mu.Lock()
+	o.entries = nil
+	o.mu.Unlock()
+}
+
+// Filter returns an observer holding the captured entries for which keep
+// returns true.
+func (o *Observer) Filter(keep func(Entry) bool) *Observer {
+	filtered := &Observer{}
+	for _, e := range o.Entries() {
+		if keep(e) {
+			filtered.entries = append(filtered.entries, e)
+		}
+	}
+	return filtered
+}
+
+// FilterLevel keeps the entries at level.
+func (o *Observer) FilterLevel(level uint32) *Observer {
+	return o.Filter(func(e Entry) bool { return e.Level == level })
+}
+
+// FilterMessage keeps the entries with message msg.
+func (o *Observer) FilterMessage(msg string) *Observer {
+	return o.Filter(func(e Entry) bool { return e.Message == msg })
+}
+
+// FilterMessageContains keeps the entries whose message contains sub.
+func (o *Observer) FilterMessageContains(sub string) *Observer {
+	return o.Filter(func(e Entry) bool { return strings.Contains(e.Message, sub) })
+}
+
+// FilterField keeps the entries with the field key equal to val, which is
+// converted like the field values, so FilterField("n", 1) matches int64(1).
+func (o *Observer) FilterField(key string, val interface{}) *Observer {
+	val = fieldValue(slog.AnyValue(val))
+	return o.Filter(func(e Entry) bool {
+		v, ok := e.Fields[key]
+		return ok && reflect.DeepEqual(v, val)
+	})
+}
+
+// TB is the part of testing.TB the assertions use, for them to report to
+// a fake in tests of test helpers.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// AssertCount reports an error listing the entries if there are not n.
+func (o *Observer) AssertCount(t TB, n int) bool {
+	t.Helper()
+	entries := o.Entries()
+	if len(entries) == n {
+		return true
+	}
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = "\t" + e.String()
+	}
+	t.Errorf("logtest: want %d entries, got %d:\n%s", n, len(entries), strings.Join(lines, "\n"))
+	return false
+}
+
+// observerHandler is the slog.Handler of NewObserver's logger, which does
+// not call WithAttrs or WithGroup.
+type observerHandler struct {
+	o *Observer
+}
+
+func (h observerHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h observerHandler) Handle(_ context.Context, r slog.Record) error {
+	e := Entry{
+		Time:    r.Time,
+		Level:   pkg.LevelFromSlog(r.Level),
+		Message: r.Message,
+		Fields:  make(map[string]interface{}, r.NumAttrs()),
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		switch attr.Key {
+		case "logger":
+			e.Logger = attr.Value.String()
+		case "file":
+			e.Caller = attr.Value.String()
+		default:
+			e.Fields[attr.Key] = fieldValue(attr.Value)
+		}
+		return true
+	})
+	h.o.mu.Lock()
+	h.o.entries = append(h.o.entries, e)
+	h.o.mu.Unlock()
+	return nil
+}
+
+func (h observerHandler) WithAttrs([]slog.Attr) slog.Handler {
+	return h
+}
+
+func (h observerHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+func fieldValue(v slog.Value) interface{} {
+	v = v.Resolve()
+	if v.Kind() != slog.KindGroup {
+		return v.Any()
+	}
+	m := map[string]interface{}{}
+	for _, attr := range v.Group() {
+		m[attr.Key] = fieldValue(attr.Value)
+	}
+	return m
+}
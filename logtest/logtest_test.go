@@ -0,0 +1,113 @@
+package logtest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/mushroomsir/logger/alog"
+	"github.com/mushroomsir/logger/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserver(t *testing.T) {
+	require := require.New(t)
+	logger, logs := NewObserver()
+
+	logger.Info("message", "charged", "user", "u1", "amount", 10)
+	logger.Named("db").Err("message", "query failed", "error", errors.New("timeout"), "q", map[string]interface{}{"table": "users"})
+	logger.Debug("message", "charged", "user", "u2", "amount", 2.5)
+	logger.Infof("%d items", 3)
+
+	require.Equal(4, logs.Len())
+	require.Equal([]string{"charged", "query failed", "charged", "3 items"}, logs.Messages())
+
+	e := logs.Entries()[0]
+	require.Equal(pkg.InfoLevel, e.Level)
+	require.Equal(map[string]interface{}{"user": "u1", "amount": int64(10)}, e.Fields)
+	require.Contains(e.Caller, "logtest/logtest_test.go:")
+	require.False(e.Time.IsZero())
+
+	e = logs.FilterLevel(pkg.ErrLevel).Entries()[0]
+	require.Equal("db", e.Logger)
+	require.Equal(map[string]interface{}{"error": "timeout", "q": map[string]interface{}{"table": "users"}}, e.Fields)
+
+	require.True(logs.FilterMessage("charged").AssertCount(t, 2))
+	require.True(logs.FilterMessage("charged").FilterField("amount", 10).AssertCount(t, 1))
+	require.True(logs.FilterField("amount", 2.5).FilterLevel(pkg.DebugLevel).AssertCount(t, 1))
+	require.True(logs.FilterMessageContains("items").AssertCount(t, 1))
+	require.True(logs.FilterField("missing", nil).AssertCount(t, 0))
+
+	fake := &fakeTB{}
+	require.False(logs.FilterLevel(pkg.WarningLevel).AssertCount(fake, 1))
+	require.Equal([]string{"logtest: want 1 entries, got 0:\n"}, fake.errors)
+
+	logs.Reset()
+	require.Equal(0, logs.Len())
+
+	logger.SetLevel(pkg.WarningLevel)
+	logger.Info("message", "hidden")
+	require.Equal(0, logs.Len())
+}
+
+func TestAlog(t *testing.T) {
+	prev := alog.Root()
+
+	t.Run("swapped", func(t *testing.T) {
+		require := require.New(t)
+		logs := Alog(t)
+		require.NotEqual(prev, alog.Root())
+		alog.Warning("message", "low disk", "free", 5)
+		entries := logs.FilterLevel(pkg.WarningLevel).Entries()
+		require.Equal(1, len(entries))
+		require.Contains(entries[0].Caller, "logtest/logtest_test.go:")
+		require.Equal(int64(5), entries[0].Fields["free"])
+	})
+	require.Equal(t, prev, alog.Root())
+}
+
+func TestNew(t *testing.T) {
+	require := require.New(t)
+	var logger *pkg.Logger
+	t.Run("log", func(t *testing.T) {
+		logger = New(t)
+		logger.Debug("message", "written to t.Log")
+	})
+	// dropped instead of panicking once the test finished
+	logger.Info("message", "late")
+	require.NotNil(logger)
+}
+
+func TestAlogParallel(t *testing.T) {
+	prev := alog.SetDefault(pkg.New(io.Discard, pkg.Options{}))
+	defer alog.SetDefault(prev)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			alog.Info("message", "background", "i", i)
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		t.Run("swap", func(t *testing.T) {
+			Alog(t)
+		})
+	}
+	<-done
+}
+
+// fakeTB records the failures reported to it.
+type fakeTB struct {
+	errors, fatals []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) Fatalf(format string, args ...interface{}) {
+	f.fatals = append(f.fatals, fmt.Sprintf(format, args...))
+}
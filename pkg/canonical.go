@@ -0,0 +1,216 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// canonicalMessage is the message of canonical log lines.
+const canonicalMessage = "canonical log line"
+
+// CanonicalLine accumulates the fields, counters and timings of a unit of
+// work, such as a request, and writes them as a single INFO record when it
+// ends, with its duration and status:
+//
+//	ctx, line := logger.StartCanonical(r.Context(), "path", r.URL.Path)
+//	defer line.End()
+//	...
+//	pkg.CanonicalFrom(ctx).Count("db_queries", 1)
+//
+// Its methods are safe for concurrent use and do nothing on a nil
+// CanonicalLine or once it ended.
+type CanonicalLine struct {
+	logger   *Logger
+	start    time.Time
+	mu       sync.Mutex
+	ended    bool
+	keys     []string
+	fields   map[string]interface{}
+	counters map[string]int64
+	timings  map[string]time.Duration
+	status   interface{}
+}
+
+// Canonical starts a CanonicalLine of the logger with the fields kv, key
+// value pairs.
+func (a *Logger) Canonical(kv ...interface{}) *CanonicalLine {
+	c := &CanonicalLine{
+		logger:   a,
+		start:    a.now(),
+		fields:   map[string]interface{}{},
+		counters: map[string]int64{},
+		timings:  map[string]time.Duration{},
+	}
+	return c.Set(kv...)
+}
+
+// StartCanonical starts a CanonicalLine of the logger and returns it with
+// a copy of ctx carrying it, see CanonicalFrom.
+func (a *Logger) StartCanonical(ctx context.Context, kv ...interface{}) (context.Context, *CanonicalLine) {
+	c := a.Canonical(kv...)
+	return WithCanonical(ctx, c), c
+}
+
+type canonicalKey struct{}
+
+// WithCanonical returns a copy of ctx carrying c.
+func WithCanonical(ctx context.Context, c *CanonicalLine) context.Context {
+	return context.WithValue(ctx, canonicalKey{}, c)
+}
+
+// CanonicalFrom returns the CanonicalLine carried by ctx, nil if none.
+func CanonicalFrom(ctx context.Context) *CanonicalLine {
+	c, _ := ctx.Value(canonicalKey{}).(*CanonicalLine)
+	return c
+}
+
+// Set sets the fields kv, key value pairs, replacing the values of the
+// keys already set. Errors are recorded as their message.
+func (c *CanonicalLine) Set(kv ...interface{}) *CanonicalLine {
+	if c == nil {
+		return c
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ended {
+		return c
+	}
+	for i := 0; i < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			key = fmt.Sprint(kv[i])
+		}
+		var val interface{}
+		if i+1 < len(kv) {
+			val = kv[i+1]
+			if err, ok := val.(error); ok {
+				val = err.Error()
+			}
+		}
+		if _, ok := c.fields[key]; !ok {
+			c.keys = append(c.keys, key)
+		}
+		c.fields[key] = val
+	}
+	return c
+}
+
+// Count adds n to the counter key.
+func (c *CanonicalLine) Count(key string, n int64) *CanonicalLine {
+	if c == nil {
+		return c
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.ended {
+		c.counters[key] += n
+	}
+	return c
+}
+
+// AddDuration adds d to the timing key.
+func (c *CanonicalLine) AddDuration(key string, d time.Duration) *CanonicalLine {
+	if c == nil {
+		return c
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.ended {
+		c.timings[key] += d
+	}
+	return c
+}
+
+// Time starts timing key and returns the func adding the time elapsed to
+// it, as in defer line.Time("db")().
+func (c *CanonicalLine) Time(key string) (stop func()) {
+	if c == nil {
+		return func() {}
+	}
+	start := c.logger.now()
+	return func() {
+		c.AddDuration(key, c.logger.now().Sub(start))
+	}
+}
+
+// SetStatus sets the status of the record, "ok" by default.
+func (c *CanonicalLine) SetStatus(status interface{}) *CanonicalLine {
+	if c == nil {
+		return c
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.ended {
+		c.status = status
+	}
+	return c
+}
+
+// End writes the record, only the first time it is called. Deferred, it
+// also writes it when the function panics, with the status "panic" and the
+// field "panic", then panics again with the same value.
+func (c *CanonicalLine) End() {
+	if c == nil {
+		return
+	}
+	r := recover()
+	if r != nil {
+		c.Set("panic", fmt.Sprint(r))
+		c.SetStatus("panic")
+	}
+	c.write()
+	if r != nil {
+		panic(r)
+	}
+}
+
+func (c *CanonicalLine) write() {
+	a := c.logger
+	c.mu.Lock()
+	if c.ended {
+		c.mu.Unlock()
+		return
+	}
+	c.ended = true
+	kv := make([]interface{}, 0, 2*(len(c.keys)+len(c.counters)+len(c.timings))+6)
+	kv = append(kv, message, canonicalMessage)
+	for _, k := range c.keys {
+		kv = append(kv, k, c.fields[k])
+	}
+	counters := make([]string, 0, len(c.counters))
+	for k := range c.counters {
+		counters = append(counters, k)
+	}
+	sort.Strings(counters)
+	for _, k := range counters {
+		kv = append(kv, k, c.counters[k])
+	}
+	timings := make([]string, 0, len(c.timings))
+	for k := range c.timings {
+		timings = append(timings, k)
+	}
+	sort.Strings(timings)
+	for _, k := range timings {
+		kv = append(kv, k, c.timings[k].String())
+	}
+	status := c.status
+	if status == nil {
+		status = "ok"
+	}
+	kv = append(kv, "duration", a.now().Sub(c.start).String(), "status", status)
+	c.mu.Unlock()
+
+	if !a.checkLevelFile(InfoLevel, "") {
+		return
+	}
+	var v interface{} = kvLog(log{}, kv, nil)
+	if a.fieldOrder {
+		o := &orderedLog{}
+		o.log = kvLog(log{}, kv, &o.keys)
+		v = o
+	}
+	a.Output(a.now(), InfoLevel, v)
+}
@@ -0,0 +1,74 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonical(t *testing.T) {
+	t.Run("end", func(t *testing.T) {
+		require := require.New(t)
+		buf := new(bytes.Buffer)
+		now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock := func() time.Time { return now }
+		logger := New(buf, Options{EnableJSON: true, Clock: clock, Format: "json", FieldOrder: true})
+
+		ctx, line := logger.StartCanonical(context.Background(), "method", "GET", "path", "/pay")
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				CanonicalFrom(ctx).Count("db_queries", 1)
+			}()
+		}
+		wg.Wait()
+		stop := CanonicalFrom(ctx).Time("db")
+		now = now.Add(30 * time.Millisecond)
+		stop()
+		CanonicalFrom(ctx).AddDuration("db", 5*time.Millisecond).Set("user", "u1", "error", errors.New("declined")).SetStatus(402)
+		now = now.Add(time.Second)
+		line.End()
+		line.End()
+		line.Set("late", true)
+
+		require.Equal(`{"timestamp":"2020-01-01T00:00:01.03Z","level":"INFO","message":"canonical log line","method":"GET","path":"/pay","user":"u1","error":"declined","db_queries":10,"db":"35ms","duration":"1.03s","status":402}`+"\n", buf.String())
+		require.Nil(CanonicalFrom(context.Background()))
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		require := require.New(t)
+		buf := new(bytes.Buffer)
+		logger := New(buf, Options{EnableJSON: true, Deterministic: true}).SetJSONLog()
+
+		require.PanicsWithValue("boom", func() {
+			line := logger.Canonical("path", "/pay")
+			defer line.End()
+			panic("boom")
+		})
+		require.Equal(`{"duration":"0s","level":"INFO","message":"canonical log line","panic":"boom","path":"/pay","status":"panic","timestamp":"2000-01-01T00:00:00Z"}`+"\n", buf.String())
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		require := require.New(t)
+		var line *CanonicalLine
+		require.NotPanics(func() {
+			defer line.Time("db")()
+			line.Set("a", 1).Count("b", 1).AddDuration("c", time.Second).SetStatus("ok").End()
+		})
+	})
+
+	t.Run("level", func(t *testing.T) {
+		require := require.New(t)
+		buf := new(bytes.Buffer)
+		logger := New(buf, Options{EnableJSON: true}).SetLevel(WarningLevel)
+		logger.Canonical("path", "/pay").End()
+		require.Empty(buf.String())
+	})
+}
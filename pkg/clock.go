@@ -0,0 +1,40 @@
+package pkg
+
+import (
+	"sync"
+	"time"
+)
+
+// deterministicTime is the time of the records of deterministic loggers.
+var deterministicTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func deterministicClock() time.Time {
+	return deterministicTime
+}
+
+// now returns the time of a record written now.
+func (a *Logger) now() time.Time {
+	return a.clock().UTC()
+}
+
+// goroutineSeq numbers goroutines in the order they ask for their ID.
+type goroutineSeq struct {
+	mu  sync.Mutex
+	ids map[uint64]uint64
+}
+
+func newGoroutineSeq() *goroutineSeq {
+	return &goroutineSeq{ids: map[uint64]uint64{}}
+}
+
+func (s *goroutineSeq) id() uint64 {
+	gid := GoroutineID()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.ids[gid]
+	if !ok {
+		id = uint64(len(s.ids) + 1)
+		s.ids[gid] = id
+	}
+	return id
+}
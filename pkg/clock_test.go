@@ -0,0 +1,48 @@
+package pkg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClock(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	now := time.Date(2020, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	logger := New(buf, Options{
+		EnableJSON:  true,
+		EnableGoID:  true,
+		Clock:       func() time.Time { return now },
+		GoroutineID: func() uint64 { return 42 },
+	})
+	logger.Info("a", 1)
+	require.Equal("[2020-05-01T10:00:00Z] INFO {\"a\":1,\"goID\":42}\n", buf.String())
+	buf.Reset()
+
+	limited := logger.Named("limited").Every(time.Minute)
+	for i, step := range []time.Duration{0, 30 * time.Second, time.Minute} {
+		now = now.Add(step)
+		limited.Info("n", i+1)
+	}
+	require.Equal(2, strings.Count(buf.String(), "\n"))
+	require.Contains(buf.String(), "[2020-05-01T10:01:30Z] INFO {\"goID\":42,\"logger\":\"limited\",\"n\":3}")
+	buf.Reset()
+
+	logger = New(buf, Options{EnableJSON: true, EnableGoID: true, Deterministic: true, Format: "logfmt"})
+	done := make(chan struct{})
+	logger.Info("a", 1)
+	go func() {
+		logger.Info("a", 2)
+		close(done)
+	}()
+	<-done
+	logger.Info("a", 3)
+	require.Equal(`timestamp=2000-01-01T00:00:00Z level=INFO a=1 goID=1
+timestamp=2000-01-01T00:00:00Z level=INFO a=2 goID=2
+timestamp=2000-01-01T00:00:00Z level=INFO a=3 goID=1
+`, buf.String())
+}
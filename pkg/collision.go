@@ -0,0 +1,113 @@
+package pkg
+
+import (
+	"strconv"
+)
+
+// Collision policies for the fields named like the reserved keys of a record.
+const (
+	collisionOverwrite uint8 = iota
+	collisionPrefix
+	collisionNest
+	collisionSuffix
+)
+
+// collisions maps the names accepted by Options.Collisions to the policies.
+var collisions = map[string]uint8{
+	"":          collisionOverwrite,
+	"overwrite": collisionOverwrite,
+	"prefix":    collisionPrefix,
+	"nest":      collisionNest,
+	"suffix":    collisionSuffix,
+}
+
+// collisionKey is the key the prefix and nest policies move fields under.
+const collisionKey = "fields"
+
+func (a *Logger) checkCollisions() bool {
+	return a.collision != collisionOverwrite || a.onCollision != nil
+}
+
+// reservedKeys returns the keys Output adds to the records of the logger,
+// with their names in the logger's schema. The file key, added by magic, is
+// only returned renamed.
+func (a *Logger) reservedKeys() []string {
+	keys := []string{timestampKey, levelKey}
+	if a.name != "" {
+		keys = append(keys, loggerName)
+	}
+	if a.enableGoID {
+		keys = append(keys, goID)
+	}
+	var reserved []string
+	for _, k := range keys {
+		reserved = append(reserved, a.keys.outputNames(k)...)
+	}
+	if a.enableFileLine {
+		reserved = append(reserved, a.keys.outputNames(file)[1:]...)
+	}
+	return reserved
+}
+
+// collide moves the fields of m named like the reserved keys out of their
+// way following the logger's collision policy, renaming them in keys too,
+// after reporting them to OnCollision.
+func (a *Logger) collide(m log, keys []string, reserved []string) {
+	var nested map[string]interface{}
+	for _, k := range reserved {
+		v, ok := m[k]
+		if !ok {
+			continue
+		}
+		if a.onCollision != nil {
+			a.onCollision(k)
+		}
+		to := collisionKey
+		switch a.collision {
+		case collisionPrefix:
+			to = freeKey(m, collisionKey+"."+k)
+			m[to] = v
+		case collisionSuffix:
+			to = freeKey(m, k)
+			m[to] = v
+		case collisionNest:
+			if nested == nil {
+				nested = map[string]interface{}{}
+				if f, ok := m[collisionKey]; ok {
+					if fm, ok := f.(map[string]interface{}); ok {
+						for fk, fv := range fm {
+							nested[fk] = fv
+						}
+					} else {
+						nested[collisionKey] = f
+					}
+				}
+			}
+			nested[k] = v
+		default:
+			continue
+		}
+		delete(m, k)
+		for i := range keys {
+			if keys[i] == k {
+				keys[i] = to
+			}
+		}
+	}
+	if nested != nil {
+		m[collisionKey] = nested
+	}
+}
+
+// freeKey returns k if it is not in m, k_1, k_2... otherwise.
+func freeKey(m log, k string) string {
+	if _, ok := m[k]; !ok {
+		return k
+	}
+	for i := 1; ; i++ {
+		key := k + "_" + strconv.Itoa(i)
+		if _, ok := m[key]; !ok {
+			return key
+		}
+	}
+}
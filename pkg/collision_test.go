@@ -0,0 +1,81 @@
+package pkg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollisions(t *testing.T) {
+	t.Run("overwrite", func(t *testing.T) {
+		require := require.New(t)
+		buf := new(bytes.Buffer)
+		logger := New(buf, Options{EnableJSON: true, EnableFileLine: true, Deterministic: true}).SetJSONLog()
+		logger.Info("level", "x", "file", "a.go", "message", "hi")
+		require.Equal(`{"file":"a.go","level":"INFO","message":"hi","timestamp":"2000-01-01T00:00:00Z"}`+"\n", buf.String())
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		require := require.New(t)
+		buf := new(bytes.Buffer)
+		logger := New(buf, Options{EnableJSON: true, EnableFileLine: true, Deterministic: true, Collisions: "prefix"}).SetJSONLog()
+		logger.Info("level", "x", "file", "a.go", "message", "hi")
+		require.Regexp(`^\{"fields.file":"a.go","fields.level":"x","file":"logger/pkg/collision_test.go:\d+","level":"INFO","message":"hi","timestamp":"2000-01-01T00:00:00Z"\}\n$`, buf.String())
+		buf.Reset()
+
+		logger.Named("db").SetFields("logger", "sql").Info("message", "hi")
+		require.Equal(`{"fields.logger":"sql","file":`, buf.String()[:30])
+	})
+
+	t.Run("nest", func(t *testing.T) {
+		require := require.New(t)
+		buf := new(bytes.Buffer)
+		logger := New(buf, Options{EnableJSON: true, EnableGoID: true, Deterministic: true, Collisions: "nest", FieldOrder: true}).SetJSONLog()
+		logger.Info("user", "u1", "goID", 7, "timestamp", "yesterday", "message", "hi")
+		require.Equal(`{"timestamp":"2000-01-01T00:00:00Z","level":"INFO","goID":1,"message":"hi","user":"u1","fields":{"goID":7,"timestamp":"yesterday"}}`+"\n", buf.String())
+		buf.Reset()
+
+		logger.Info("fields", "f", "level", "x")
+		require.Contains(buf.String(), `"fields":{"fields":"f","level":"x"}`)
+	})
+
+	t.Run("suffix", func(t *testing.T) {
+		require := require.New(t)
+		buf := new(bytes.Buffer)
+		logger := New(buf, Options{EnableJSON: true, Deterministic: true, Collisions: "suffix"})
+		logger.Info("level", "x", "level_1", "y", "message", "hi")
+		require.Equal(`[2000-01-01T00:00:00Z] INFO {"level_1":"y","level_2":"x","message":"hi"}`+"\n", buf.String())
+	})
+
+	t.Run("schema", func(t *testing.T) {
+		require := require.New(t)
+		buf := new(bytes.Buffer)
+		logger := New(buf, Options{EnableJSON: true, EnableFileLine: true, Deterministic: true, Format: "json", Keys: ECSKeys, Collisions: "prefix"})
+		logger.Info("log.level", "x", "log.origin.file.name", "a.go", "timestamp", "t")
+		require.Regexp(`^\{"@timestamp":"2000-01-01T00:00:00Z","fields.log.level":"x","fields.log.origin.file.name":"a.go","fields.timestamp":"t","log.level":"info","log.origin.file.line":\d+,"log.origin.file.name":"logger/pkg/collision_test.go"\}\n$`, buf.String())
+	})
+
+	t.Run("report", func(t *testing.T) {
+		require := require.New(t)
+		buf := new(bytes.Buffer)
+		var collided []string
+		onCollision := func(key string) { collided = append(collided, key) }
+		logger := New(buf, Options{EnableJSON: true, EnableFileLine: true, Deterministic: true, OnCollision: onCollision}).SetJSONLog()
+		logger.Info("level", "x", "file", "a.go", "message", "hi")
+		require.Equal([]string{"file", "level"}, collided)
+		require.Equal(`{"file":"a.go","level":"INFO","message":"hi","timestamp":"2000-01-01T00:00:00Z"}`+"\n", buf.String())
+		buf.Reset()
+
+		collided = nil
+		logger.Named("db").Info("user", "u1")
+		require.Empty(collided)
+		require.Contains(buf.String(), `"user":"u1"`)
+		buf.Reset()
+
+		prefix := New(buf, Options{EnableJSON: true, Deterministic: true, Collisions: "prefix", OnCollision: onCollision}).SetJSONLog()
+		prefix.Info("timestamp", "t")
+		require.Equal([]string{"timestamp"}, collided)
+		require.Contains(buf.String(), `"fields.timestamp":"t"`)
+	})
+}
@@ -0,0 +1,24 @@
+package pkg
+
+// Log writes a record at level like the level methods, Info for InfoLevel
+// and so on. depth is the number of frames between the call site and Log,
+// for wrappers such as alog's functions: 0 is the caller of Log.
+func (a *Logger) Log(depth int, level uint32, kv ...interface{}) {
+	if a.checkLevelSkip(level, a.skip+depth) {
+		a.output(a.now(), level, a.magicSkip(a.skip+depth, kv), a.skip+depth)
+	}
+}
+
+// Logf is Log for the *f methods.
+func (a *Logger) Logf(depth int, level uint32, format string, args ...interface{}) {
+	if a.checkLevelSkip(level, a.skip+depth) {
+		a.output(a.now(), level, a.magicSkip(a.skip+depth, a.printfKV(format, args)), a.skip+depth)
+	}
+}
+
+// Logt is Log for the *t methods.
+func (a *Logger) Logt(depth int, level uint32, template string, args ...interface{}) {
+	if a.checkLevelSkip(level, a.skip+depth) {
+		a.output(a.now(), level, a.magicSkip(a.skip+depth, a.templateKV(template, args)), a.skip+depth)
+	}
+}
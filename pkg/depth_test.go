@@ -0,0 +1,36 @@
+package pkg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogDepth(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{
+		EnableJSON:     true,
+		EnableFileLine: true,
+	})
+	logger.SetLevel(InfoLevel)
+
+	warn := func(kv ...interface{}) {
+		logger.Log(1, WarningLevel, kv...)
+	}
+	warn("a", 1)
+	require.Contains(buf.String(), `WARNING {"a":1,"file":"logger/pkg/depth_test.go:22"}`)
+	buf.Reset()
+
+	logger.Logf(0, ErrLevel, "n=%d", 1)
+	require.Contains(buf.String(), `ERR {"file":"logger/pkg/depth_test.go:26","message":"n=1"}`)
+	buf.Reset()
+
+	logger.Logt(0, NoticeLevel, "user {User}", "u1")
+	require.Contains(buf.String(), `NOTICE {"User":"u1","file":"logger/pkg/depth_test.go:30"`)
+	buf.Reset()
+
+	logger.Log(0, DebugLevel, "a", 1)
+	require.Empty(buf.String())
+}
@@ -0,0 +1,348 @@
+package pkg
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// LogObjectMarshaler is implemented by types writing themselves as an
+// object in records, whatever their JSON encoding:
+//
+//	func (o Order) MarshalLogObject(enc pkg.ObjectEncoder) error {
+//		enc.AddString("id", o.ID)
+//		enc.AddInt64("cents", o.Cents)
+//		return enc.AddArray("items", o.Items)
+//	}
+//
+// Values are detected in kv arguments, bound fields and nested maps and
+// slices, for all output formats. If MarshalLogObject returns an error, the
+// fields added are written with the error as "marshal_error".
+type LogObjectMarshaler interface {
+	MarshalLogObject(ObjectEncoder) error
+}
+
+// LogArrayMarshaler is LogObjectMarshaler for types written as an array.
+type LogArrayMarshaler interface {
+	MarshalLogArray(ArrayEncoder) error
+}
+
+// ObjectEncoder adds the fields of a LogObjectMarshaler.
+type ObjectEncoder interface {
+	AddString(key, val string)
+	AddInt64(key string, val int64)
+	AddUint64(key string, val uint64)
+	AddFloat64(key string, val float64)
+	AddBool(key string, val bool)
+	AddDuration(key string, val time.Duration)
+	AddTime(key string, val time.Time)
+	AddObject(key string, val LogObjectMarshaler) error
+	AddArray(key string, val LogArrayMarshaler) error
+	// Add adds a value of any type, encoded like kv values.
+	Add(key string, val interface{})
+}
+
+// ArrayEncoder appends the elements of a LogArrayMarshaler.
+type ArrayEncoder interface {
+	AppendString(val string)
+	AppendInt64(val int64)
+	AppendUint64(val uint64)
+	AppendFloat64(val float64)
+	AppendBool(val bool)
+	AppendDuration(val time.Duration)
+	AppendTime(val time.Time)
+	AppendObject(val LogObjectMarshaler) error
+	AppendArray(val LogArrayMarshaler) error
+	// Append appends a value of any type, encoded like kv values.
+	Append(val interface{})
+}
+
+// LogValuer is implemented by values computed only for the records which
+// are written, e.g. expensive debug values:
+//
+//	logger.Debug("state", pkg.Lazy(func() interface{} { return dump(state) }))
+//
+// LogValue is called after the level check, once per record whatever the
+// number of sinks, and its result encoded like kv values.
+type LogValuer interface {
+	LogValue() interface{}
+}
+
+// Lazy returns a LogValuer calling f.
+func Lazy(f func() interface{}) LogValuer {
+	return lazy(f)
+}
+
+type lazy func() interface{}
+
+func (f lazy) LogValue() interface{} {
+	return f()
+}
+
+// maxValuerDepth bounds the chain of LogValuers returning LogValuers.
+const maxValuerDepth = 8
+
+// marshalError is the key of the error returned by a log marshaler.
+const marshalError = "marshal_error"
+
+var (
+	valuerType          = reflect.TypeOf((*LogValuer)(nil)).Elem()
+	objectMarshalerType = reflect.TypeOf((*LogObjectMarshaler)(nil)).Elem()
+	arrayMarshalerType  = reflect.TypeOf((*LogArrayMarshaler)(nil)).Elem()
+)
+
+// encodedType reports whether encodeValue changes values of type t.
+// The depth limit stops at recursive types such as type T []T.
+func encodedType(t reflect.Type) bool {
+	for i := 0; i < maxJSONDepth; i++ {
+		if t.Implements(valuerType) || t.Implements(objectMarshalerType) || t.Implements(arrayMarshalerType) {
+			return true
+		}
+		switch t.Kind() {
+		case reflect.Map:
+			if !textKey(t.Key()) {
+				return false
+			}
+			t = t.Elem()
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			t = t.Elem()
+		case reflect.Struct:
+			return planOf(t) != nil
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// mapEncoder is the ObjectEncoder building the map of an object.
+type mapEncoder struct {
+	m     map[string]interface{}
+	r     *redactor
+	depth int
+}
+
+func (e *mapEncoder) AddString(key, val string)                 { e.m[key] = val }
+func (e *mapEncoder) AddInt64(key string, val int64)            { e.m[key] = val }
+func (e *mapEncoder) AddUint64(key string, val uint64)          { e.m[key] = val }
+func (e *mapEncoder) AddFloat64(key string, val float64)        { e.m[key] = val }
+func (e *mapEncoder) AddBool(key string, val bool)              { e.m[key] = val }
+func (e *mapEncoder) AddDuration(key string, val time.Duration) { e.m[key] = val.String() }
+func (e *mapEncoder) AddTime(key string, val time.Time)         { e.m[key] = val.Format(time.RFC3339Nano) }
+
+func (e *mapEncoder) AddObject(key string, val LogObjectMarshaler) error {
+	enc := &mapEncoder{m: map[string]interface{}{}, r: e.r, depth: e.depth + 1}
+	err := val.MarshalLogObject(enc)
+	e.m[key] = enc.m
+	return err
+}
+
+func (e *mapEncoder) AddArray(key string, val LogArrayMarshaler) error {
+	enc := &sliceEncoder{s: []interface{}{}, r: e.r, depth: e.depth + 1}
+	err := val.MarshalLogArray(enc)
+	e.m[key] = enc.s
+	return err
+}
+
+func (e *mapEncoder) Add(key string, val interface{}) {
+	if err, ok := val.(error); ok && !encodedType(reflect.TypeOf(val)) {
+		val = err.Error()
+	}
+	e.m[key], _ = encodeValue(val, e.r, e.depth+1)
+}
+
+// sliceEncoder is the ArrayEncoder building the slice of an array.
+type sliceEncoder struct {
+	s     []interface{}
+	r     *redactor
+	depth int
+}
+
+func (e *sliceEncoder) AppendString(val string)          { e.s = append(e.s, val) }
+func (e *sliceEncoder) AppendInt64(val int64)            { e.s = append(e.s, val) }
+func (e *sliceEncoder) AppendUint64(val uint64)          { e.s = append(e.s, val) }
+func (e *sliceEncoder) AppendFloat64(val float64)        { e.s = append(e.s, val) }
+func (e *sliceEncoder) AppendBool(val bool)              { e.s = append(e.s, val) }
+func (e *sliceEncoder) AppendDuration(val time.Duration) { e.s = append(e.s, val.String()) }
+func (e *sliceEncoder) AppendTime(val time.Time)         { e.s = append(e.s, val.Format(time.RFC3339Nano)) }
+
+func (e *sliceEncoder) AppendObject(val LogObjectMarshaler) error {
+	enc := &mapEncoder{m: map[string]interface{}{}, r: e.r, depth: e.depth + 1}
+	err := val.MarshalLogObject(enc)
+	e.s = append(e.s, enc.m)
+	return err
+}
+
+func (e *sliceEncoder) AppendArray(val LogArrayMarshaler) error {
+	enc := &sliceEncoder{s: []interface{}{}, r: e.r, depth: e.depth + 1}
+	err := val.MarshalLogArray(enc)
+	e.s = append(e.s, enc.s)
+	return err
+}
+
+func (e *sliceEncoder) Append(val interface{}) {
+	if err, ok := val.(error); ok && !encodedType(reflect.TypeOf(val)) {
+		val = err.Error()
+	}
+	v, _ := encodeValue(val, e.r, e.depth+1)
+	e.s = append(e.s, v)
+}
+
+// encodeArgs returns kv with its log marshalers and tagged structs
+// replaced by maps and slices.
+func (a *Logger) encodeArgs(kv []interface{}) []interface{} {
+	var args []interface{}
+	r, _ := a.root.redactor.Load().(*redactor)
+	for i, v := range kv {
+		if nv, ok := encodeValue(v, r, 0); ok {
+			if args == nil {
+				args = append([]interface{}(nil), kv...)
+			}
+			args[i] = nv
+		}
+	}
+	if args == nil {
+		return kv
+	}
+	return args
+}
+
+// encodeValues replaces the log marshalers and tagged structs in the
+// fields of m by maps and slices, so every output format writes them alike.
+func (a *Logger) encodeValues(m log) {
+	r, _ := a.root.redactor.Load().(*redactor)
+	for k, v := range m {
+		if nv, ok := encodeValue(v, r, 0); ok {
+			m[k] = nv
+		}
+	}
+}
+
+// encodeValue returns v with its log marshalers and tagged structs
+// replaced by maps and slices, and whether v changed. Maps and slices are
+// copied instead of modified.
+func encodeValue(v interface{}, r *redactor, depth int) (interface{}, bool) {
+	if depth >= maxJSONDepth {
+		return v, false
+	}
+	switch val := v.(type) {
+	case nil, string, bool, int, int64, int32, uint, uint64, uint32, float64, float32:
+		return v, false
+	case LogValuer:
+		for i := 0; ; i++ {
+			v = val.LogValue()
+			next, ok := v.(LogValuer)
+			if !ok {
+				break
+			}
+			if i == maxValuerDepth {
+				return nil, true
+			}
+			val = next
+		}
+		if err, ok := v.(error); ok && !encodedType(reflect.TypeOf(v)) {
+			return err.Error(), true
+		}
+		v, _ = encodeValue(v, r, depth+1)
+		return v, true
+	case LogObjectMarshaler:
+		enc := &mapEncoder{m: map[string]interface{}{}, r: r, depth: depth}
+		if err := val.MarshalLogObject(enc); err != nil {
+			enc.m[marshalError] = err.Error()
+		}
+		return enc.m, true
+	case LogArrayMarshaler:
+		enc := &sliceEncoder{s: []interface{}{}, r: r, depth: depth}
+		if err := val.MarshalLogArray(enc); err != nil {
+			enc.s = append(enc.s, map[string]interface{}{marshalError: err.Error()})
+		}
+		return enc.s, true
+	case error:
+		return v, false
+	case map[string]interface{}:
+		var m map[string]interface{}
+		for k, e := range val {
+			if ne, ok := encodeValue(e, r, depth+1); ok {
+				if m == nil {
+					m = make(map[string]interface{}, len(val))
+					for k, e := range val {
+						m[k] = e
+					}
+				}
+				m[k] = ne
+			}
+		}
+		return m, m != nil
+	case []interface{}:
+		var s []interface{}
+		for i, e := range val {
+			if ne, ok := encodeValue(e, r, depth+1); ok {
+				if s == nil {
+					s = append([]interface{}(nil), val...)
+				}
+				s[i] = ne
+			}
+		}
+		return s, s != nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+			return v, false
+		}
+		return encodeValue(rv.Elem().Interface(), r, depth)
+	case reflect.Struct:
+		p := planOf(rv.Type())
+		if p == nil {
+			return v, false
+		}
+		return p.encode(rv, r, depth), true
+	case reflect.Slice, reflect.Array:
+		if et := rv.Type().Elem(); !encodedType(et) {
+			return v, false
+		}
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return v, false
+		}
+		s := make([]interface{}, rv.Len())
+		for i := range s {
+			s[i], _ = encodeValue(rv.Index(i).Interface(), r, depth+1)
+		}
+		return s, true
+	case reflect.Map:
+		if rv.IsNil() || !encodedType(rv.Type()) {
+			return v, false
+		}
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[keyText(iter.Key())], _ = encodeValue(iter.Value().Interface(), r, depth+1)
+		}
+		return m, true
+	}
+	return v, false
+}
+
+// textKey reports whether the map keys of type t are written as text, as
+// encoding/json does for strings and integers.
+func textKey(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// keyText returns the text of the map key k, whose type satisfies textKey.
+func keyText(k reflect.Value) string {
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10)
+	}
+	return k.String()
+}
@@ -0,0 +1,66 @@
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testOrder struct {
+	ID    string
+	Cents int64
+	Items testItems
+}
+
+// MarshalJSON is the API encoding, not used by the logger.
+func (o testOrder) MarshalJSON() ([]byte, error) {
+	return []byte(`"api"`), nil
+}
+
+func (o testOrder) MarshalLogObject(enc ObjectEncoder) error {
+	enc.AddString("id", o.ID)
+	enc.AddInt64("cents", o.Cents)
+	enc.AddDuration("ttl", time.Minute)
+	enc.Add("cause", errors.New("none"))
+	return enc.AddArray("items", o.Items)
+}
+
+type testItems []string
+
+func (s testItems) MarshalLogArray(enc ArrayEncoder) error {
+	for _, item := range s {
+		enc.AppendString(item)
+	}
+	if len(s) == 0 {
+		return errors.New("no items")
+	}
+	return nil
+}
+
+func TestMarshalers(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{EnableJSON: true})
+	order := testOrder{ID: "o1", Cents: 250, Items: testItems{"a", "b"}}
+
+	logger.Info("order", order, "items", testItems{"c"})
+	require.Contains(buf.String(), `{"items":["c"],"order":{"cause":"none","cents":250,"id":"o1","items":["a","b"],"ttl":"1m0s"}}`)
+	buf.Reset()
+
+	logger.Named("bound").SetFields("orders", []testOrder{{ID: "o2"}}).Info(map[string]interface{}{"nested": []interface{}{&order}})
+	require.Contains(buf.String(), `"orders":[{"cause":"none","cents":0,"id":"o2","items":[],"marshal_error":"no items","ttl":"1m0s"}]`)
+	require.Contains(buf.String(), `"nested":[{"cause":"none","cents":250,"id":"o1","items":["a","b"],"ttl":"1m0s"}]`)
+	buf.Reset()
+
+	logfmt := New(buf, Options{EnableJSON: true, Format: "logfmt"})
+	logfmt.Info("items", testItems{"x", "y"})
+	require.Contains(buf.String(), `items="[\"x\",\"y\"]"`)
+	buf.Reset()
+
+	text := New(buf)
+	text.Info("items ", testItems{"x", "y"})
+	require.Contains(buf.String(), `{"message":"items [x y]"}`)
+}
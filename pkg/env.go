@@ -0,0 +1,81 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// OptionsFromEnv returns options read from environment variables, starting
+// from the first of base if given. With prefix "LOG" the variables are:
+//
+//	LOG_LEVEL        level name or number, see ParseLevel
+//	LOG_FORMAT       text, json, logfmt or console
+//	LOG_TIME_FORMAT  time layout, see time.Format
+//	LOG_FILE_LINE    boolean, see Options.EnableFileLine
+//	LOG_GOID         boolean, see Options.EnableGoID
+//	LOG_OUTPUT       stderr, stdout or the path of a file to append to
+//
+// Unset or empty variables leave the option unchanged. An error names the
+// first variable with an invalid value.
+func OptionsFromEnv(prefix string, base ...Options) (Options, error) {
+	var opt Options
+	if len(base) > 0 {
+		opt = base[0]
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+	lookup := func(name string) (string, string, bool) {
+		name = prefix + name
+		val := strings.TrimSpace(os.Getenv(name))
+		return name, val, val != ""
+	}
+
+	if name, val, ok := lookup("LEVEL"); ok {
+		if _, valid := lookupLevel(val); !valid {
+			return opt, fmt.Errorf("%s: invalid level %q", name, val)
+		}
+		opt.Level = val
+	}
+	if name, val, ok := lookup("FORMAT"); ok {
+		if _, valid := formats[strings.ToLower(val)]; !valid {
+			return opt, fmt.Errorf("%s: invalid format %q, want text, json, logfmt or console", name, val)
+		}
+		opt.Format = val
+	}
+	if _, val, ok := lookup("TIME_FORMAT"); ok {
+		opt.TimeFormat = val
+	}
+	for _, b := range []struct {
+		name string
+		opt  *bool
+	}{
+		{"FILE_LINE", &opt.EnableFileLine},
+		{"GOID", &opt.EnableGoID},
+	} {
+		if name, val, ok := lookup(b.name); ok {
+			enable, err := strconv.ParseBool(val)
+			if err != nil {
+				return opt, fmt.Errorf("%s: invalid boolean %q", name, val)
+			}
+			*b.opt = enable
+		}
+	}
+	if name, val, ok := lookup("OUTPUT"); ok {
+		switch strings.ToLower(val) {
+		case "stderr":
+			opt.Out = os.Stderr
+		case "stdout":
+			opt.Out = os.Stdout
+		default:
+			f, err := os.OpenFile(val, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+			if err != nil {
+				return opt, fmt.Errorf("%s: %v", name, err)
+			}
+			opt.Out = f
+		}
+	}
+	return opt, nil
+}
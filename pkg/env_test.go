@@ -0,0 +1,74 @@
+package pkg
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func setenv(t *testing.T, kv ...string) {
+	for i := 0; i < len(kv); i += 2 {
+		os.Setenv(kv[i], kv[i+1])
+	}
+	t.Cleanup(func() {
+		for i := 0; i < len(kv); i += 2 {
+			os.Unsetenv(kv[i])
+		}
+	})
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	require := require.New(t)
+
+	opt, err := OptionsFromEnv("TEST_LOG", Options{EnableJSON: true, Skip: 4})
+	require.Nil(err)
+	require.Equal(Options{EnableJSON: true, Skip: 4}, opt)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	setenv(t,
+		"TEST_LOG_LEVEL", "debug",
+		"TEST_LOG_FORMAT", "logfmt",
+		"TEST_LOG_TIME_FORMAT", "2006",
+		"TEST_LOG_FILE_LINE", "true",
+		"TEST_LOG_GOID", "0",
+		"TEST_LOG_OUTPUT", path,
+	)
+	opt, err = OptionsFromEnv("TEST_LOG_", Options{EnableGoID: true})
+	require.Nil(err)
+	require.Equal("debug", opt.Level)
+	require.Equal("logfmt", opt.Format)
+	require.Equal("2006", opt.TimeFormat)
+	require.True(opt.EnableFileLine)
+	require.False(opt.EnableGoID)
+	require.NotNil(opt.Out)
+
+	logger := New(nil, opt)
+	require.Equal(DebugLevel, logger.Level())
+	logger.Debug("a", 1)
+	opt.Out.(*os.File).Close()
+	data, err := ioutil.ReadFile(path)
+	require.Nil(err)
+	require.Regexp(`^timestamp=\d{4} level=DEBUG message=a1\n$`, string(data))
+
+	os.Setenv("TEST_LOG_OUTPUT", "stdout")
+	opt, err = OptionsFromEnv("TEST_LOG")
+	require.Nil(err)
+	require.Equal(os.Stdout, opt.Out)
+
+	for _, c := range []struct{ name, val, err string }{
+		{"TEST_LOG_LEVEL", "verbose", `TEST_LOG_LEVEL: invalid level "verbose"`},
+		{"TEST_LOG_LEVEL", "9", `TEST_LOG_LEVEL: invalid level "9"`},
+		{"TEST_LOG_FORMAT", "xml", `TEST_LOG_FORMAT: invalid format "xml", want text, json, logfmt or console`},
+		{"TEST_LOG_FILE_LINE", "yes", `TEST_LOG_FILE_LINE: invalid boolean "yes"`},
+		{"TEST_LOG_GOID", "on", `TEST_LOG_GOID: invalid boolean "on"`},
+		{"TEST_LOG_OUTPUT", filepath.Join(path, "x"), "TEST_LOG_OUTPUT: open " + filepath.Join(path, "x") + ": not a directory"},
+	} {
+		setenv(t, c.name, c.val)
+		_, err = OptionsFromEnv("TEST_LOG")
+		require.EqualError(err, c.err)
+		os.Unsetenv(c.name)
+	}
+}
@@ -0,0 +1,143 @@
+package pkg
+
+import (
+	"errors"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Cmd runs an exec.Cmd writing every line of its stdout and stderr as a
+// record with the fields "cmd" and "pid", and its start and exit as
+// separate records.
+type Cmd struct {
+	cmd         *exec.Cmd
+	logger      *Logger
+	name        string
+	stdoutLevel uint32
+	stderrLevel uint32
+	maxLine     int
+	start       time.Time
+	copies      sync.WaitGroup
+	writers     []*LineWriter
+}
+
+// Command returns a Cmd running cmd, which must not have been started and
+// whose Stdout and Stderr are replaced. Stdout lines are written at
+// InfoLevel and stderr lines at WarningLevel by default.
+func (a *Logger) Command(cmd *exec.Cmd) *Cmd {
+	return &Cmd{
+		cmd:         cmd,
+		logger:      a,
+		name:        filepath.Base(cmd.Path),
+		stdoutLevel: InfoLevel,
+		stderrLevel: WarningLevel,
+	}
+}
+
+// SetLevels sets the levels of the stdout and stderr lines.
+func (c *Cmd) SetLevels(stdout, stderr uint32) *Cmd {
+	c.stdoutLevel = stdout
+	c.stderrLevel = stderr
+	return c
+}
+
+// SetMaxLineSize sets the length from which output lines are split, see
+// LineWriter.SetMaxLineSize.
+func (c *Cmd) SetMaxLineSize(n int) *Cmd {
+	c.maxLine = n
+	return c
+}
+
+// Start starts the command and writes a record "command started" with
+// its arguments, or an ERR record if it fails to start.
+func (c *Cmd) Start() error {
+	c.cmd.Stdout, c.cmd.Stderr = nil, nil
+	stdout, err := c.cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := c.cmd.StderrPipe()
+	if err != nil {
+		stdout.Close()
+		return err
+	}
+	c.start = c.logger.clock()
+	if err := c.cmd.Start(); err != nil {
+		stdout.Close()
+		stderr.Close()
+		c.write(ErrLevel, "message", "command failed to start", "cmd", c.name, "error", err)
+		return err
+	}
+	pid := c.cmd.Process.Pid
+	kv := []interface{}{"message", "command started", "cmd", c.name, "pid", pid}
+	if len(c.cmd.Args) > 1 {
+		kv = append(kv, "args", c.cmd.Args[1:])
+	}
+	c.write(InfoLevel, kv...)
+	c.copy(stdout, c.stdoutLevel, pid)
+	c.copy(stderr, c.stderrLevel, pid)
+	return nil
+}
+
+func (c *Cmd) copy(r io.Reader, level uint32, pid int) {
+	w := c.logger.Writer(level, "cmd", c.name, "pid", pid)
+	if c.maxLine > 0 {
+		w.SetMaxLineSize(c.maxLine)
+	}
+	c.writers = append(c.writers, w)
+	c.copies.Add(1)
+	go func() {
+		defer c.copies.Done()
+		io.Copy(w, r)
+	}()
+}
+
+// Wait waits for the command to exit after writing all its output and
+// writes a record "command exited" with its exit code and duration, at
+// InfoLevel if it succeeded and ErrLevel otherwise.
+func (c *Cmd) Wait() error {
+	c.copies.Wait()
+	err := c.cmd.Wait()
+	for _, w := range c.writers {
+		w.Close()
+	}
+	duration := c.logger.clock().Sub(c.start)
+	kv := []interface{}{"message", "command exited", "cmd", c.name}
+	if c.cmd.Process != nil {
+		kv = append(kv, "pid", c.cmd.Process.Pid)
+	}
+	if c.cmd.ProcessState != nil {
+		kv = append(kv, "exit_code", c.cmd.ProcessState.ExitCode())
+	}
+	kv = append(kv, "duration", duration.String())
+	level := InfoLevel
+	var exitErr *exec.ExitError
+	if err != nil {
+		level = ErrLevel
+		if !errors.As(err, &exitErr) {
+			kv = append(kv, "error", err)
+		}
+	}
+	c.write(level, kv...)
+	return err
+}
+
+// Run starts the command and waits for it to exit.
+func (c *Cmd) Run() error {
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Wait()
+}
+
+// write writes the key value pairs kv as a record without caller.
+func (c *Cmd) write(level uint32, kv ...interface{}) {
+	a := c.logger
+	if !a.checkLevelFile(level, "") {
+		return
+	}
+	a.Output(a.now(), level, kvLog(log{}, kv, nil))
+}
@@ -0,0 +1,52 @@
+package pkg
+
+import (
+	"bytes"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs /bin/sh")
+	}
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{EnableJSON: true})
+
+	cmd := exec.Command("/bin/sh", "-c", "echo out; echo err >&2; printf partial")
+	require.Nil(logger.Command(cmd).SetLevels(NoticeLevel, ErrLevel).Run())
+	pid := cmd.Process.Pid
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Equal(5, len(lines))
+	require.Contains(lines[0], `"cmd":"sh","message":"command started","pid":`+strconv.Itoa(pid)+`}`)
+	require.Contains(buf.String(), `NOTICE {"cmd":"sh","message":"out","pid":`+strconv.Itoa(pid)+`}`)
+	require.Contains(buf.String(), `ERR {"cmd":"sh","message":"err","pid":`+strconv.Itoa(pid)+`}`)
+	require.Contains(buf.String(), `NOTICE {"cmd":"sh","message":"partial","pid":`+strconv.Itoa(pid)+`}`)
+	require.Contains(lines[4], `INFO {"cmd":"sh","duration":"`)
+	require.Contains(lines[4], `"exit_code":0,"message":"command exited","pid":`+strconv.Itoa(pid)+`}`)
+	buf.Reset()
+
+	cmd = exec.Command("/bin/sh", "-c", "exit 3")
+	err := logger.Command(cmd).Run()
+	require.NotNil(err)
+	require.Contains(buf.String(), `ERR {"cmd":"sh","duration":"`)
+	require.Contains(buf.String(), `"exit_code":3,"message":"command exited"`)
+	buf.Reset()
+
+	err = logger.Command(exec.Command("/nonexistent/cmd")).Run()
+	require.NotNil(err)
+	require.Contains(buf.String(), `ERR {"cmd":"cmd","error":"fork/exec /nonexistent/cmd: no such file or directory","message":"command failed to start"}`)
+	buf.Reset()
+
+	if _, err := exec.LookPath("/bin/true"); err != nil {
+		return
+	}
+	require.Nil(logger.Command(&exec.Cmd{Path: "/bin/true"}).Run())
+	require.Contains(buf.String(), `INFO {"cmd":"true","message":"command started","pid":`)
+}
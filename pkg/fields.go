@@ -0,0 +1,55 @@
+package pkg
+
+import "fmt"
+
+// field is a key value pair bound to a logger with SetFields.
+type field struct {
+	key string
+	val interface{}
+}
+
+// SetFields replaces the fields added to every record of the logger and
+// of its named children, kv are key value pairs. It can be called while
+// the logger is in use. Fields passed at the call site take precedence,
+// and the fields of a named logger over the ones of its parent.
+func (a *Logger) SetFields(kv ...interface{}) *Logger {
+	fields := make([]field, 0, (len(kv)+1)/2)
+	for i := 0; i < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			key = fmt.Sprint(kv[i])
+		}
+		f := field{key: key}
+		if i+1 < len(kv) {
+			f.val = kv[i+1]
+			if err, ok := f.val.(error); ok {
+				f.val = err.Error()
+			}
+		}
+		fields = append(fields, f)
+	}
+	a.fields.Store(fields)
+	return a
+}
+
+// Fields returns the key value pairs set with SetFields.
+func (a *Logger) Fields() []interface{} {
+	fields, _ := a.fields.Load().([]field)
+	kv := make([]interface{}, 0, 2*len(fields))
+	for _, f := range fields {
+		kv = append(kv, f.key, f.val)
+	}
+	return kv
+}
+
+// addFields adds the fields of a and its ancestors missing from m.
+func (a *Logger) addFields(m log) {
+	for l := a; l != nil; l = l.parent {
+		fields, _ := l.fields.Load().([]field)
+		for _, f := range fields {
+			if _, ok := m[f.key]; !ok {
+				m[f.key] = f.val
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFields(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	root := New(buf, Options{EnableJSON: true})
+	root.SetFields("service", "api", "env", "dev")
+	stripe := root.Named("stripe").SetFields("env", "prod", 1, errors.New("x"), "odd")
+	require.Equal([]interface{}{"env", "prod", "1", "x", "odd", nil}, stripe.Fields())
+
+	stripe.Info("a", 1, "service", "billing")
+	require.Contains(buf.String(), `INFO {"1":"x","a":1,"env":"prod","logger":"stripe","odd":null,"service":"billing"}`)
+	buf.Reset()
+
+	root.SetFields()
+	require.Empty(root.Fields())
+	root.Info("a")
+	require.Contains(buf.String(), `INFO {"message1":"a"}`)
+
+	level, err := LookupLevel("7")
+	require.Nil(err)
+	require.Equal(DebugLevel, level)
+	level, err = LookupLevel("loud")
+	require.EqualError(err, `invalid level "loud"`)
+	require.Equal(InfoLevel, level)
+}
@@ -0,0 +1,148 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	formatText uint8 = iota
+	formatJSON
+	formatLogfmt
+	formatConsole
+)
+
+// formats maps the names accepted by Options.Format to the output formats.
+var formats = map[string]uint8{
+	"":        formatText,
+	"text":    formatText,
+	"json":    formatJSON,
+	"logfmt":  formatLogfmt,
+	"console": formatConsole,
+}
+
+// logfmtFormat renders m as key=value pairs following the timestamp and
+// level, the other keys sorted or in order if not nil.
+func (a *Logger) logfmtFormat(t time.Time, level uint32, m log, order []string) string {
+	var b strings.Builder
+	keys, header := a.header(t.UTC().Format(a.tf), level)
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(logfmtKey(k))
+		b.WriteByte('=')
+		b.WriteString(logfmtValue(header[k]))
+	}
+	writeLogfmt(&b, m, nil, order)
+	return b.String()
+}
+
+// consoleFormat renders a line for humans: the time, level, logger name,
+// file and message followed by the other fields as logfmt pairs.
+func (a *Logger) consoleFormat(t time.Time, level uint32, m log, order []string) string {
+	var b strings.Builder
+	b.WriteString(t.UTC().Format(a.tf))
+	fmt.Fprintf(&b, " %-7s", levels[level])
+	for _, k := range []string{loggerName, file, message} {
+		if v, ok := m[k]; ok && v != nil {
+			b.WriteByte(' ')
+			b.WriteString(textValue(v))
+		}
+	}
+	writeLogfmt(&b, m, map[string]bool{loggerName: true, file: true, message: true}, order)
+	return b.String()
+}
+
+func writeLogfmt(b *strings.Builder, m log, skip map[string]bool, order []string) {
+	keys := order
+	if keys == nil {
+		keys = make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+	}
+	for _, k := range keys {
+		if skip[k] {
+			continue
+		}
+		b.WriteByte(' ')
+		b.WriteString(logfmtKey(k))
+		b.WriteByte('=')
+		b.WriteString(logfmtValue(m[k]))
+	}
+}
+
+// logfmtKey replaces the characters a logfmt key can not contain.
+func logfmtKey(k string) string {
+	if k == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError {
+			return '_'
+		}
+		return r
+	}, k)
+}
+
+// textValue renders v without quotes: strings and errors as they are,
+// booleans and numbers with fmt, other values as JSON made safe as by
+// jsonSafe, or their type.
+func textValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return val
+	case error:
+		return val.Error()
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v)
+	}
+	res, err := json.Marshal(v)
+	if err != nil {
+		safe, _ := jsonSafeValue(v, 0)
+		res, err = json.Marshal(safe)
+	}
+	var s string
+	switch {
+	case err != nil:
+		s = fmt.Sprintf("%T", v)
+	case res[0] == '"':
+		json.Unmarshal(res, &s)
+	default:
+		s = string(res)
+	}
+	return s
+}
+
+// logfmtValue renders v, quoting it if needed. Values other than strings,
+// errors, booleans and numbers are rendered as JSON.
+func logfmtValue(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+	s := textValue(v)
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	if s == "" {
+		return `""`
+	}
+	return s
+}
@@ -0,0 +1,55 @@
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{
+		EnableJSON:     true,
+		EnableFileLine: true,
+		Format:         "logfmt",
+		TimeFormat:     "2006",
+	})
+	logger.Named("db").Info("message", "hello world", "n", 1, "err", errors.New("x=1"), "tags", []string{"a"})
+	require.Regexp(`^timestamp=\d{4} level=INFO err="x=1" file=logger/pkg/format_test.go:22 logger=db message="hello world" n=1 tags="\[\\"a\\"\]"\n$`, buf.String())
+	buf.Reset()
+
+	logger = New(buf, Options{
+		EnableJSON:     true,
+		EnableFileLine: true,
+		Format:         "Console",
+		TimeFormat:     "2006",
+	})
+	logger.Named("db").Warning("message", "slow query", "ms", 1.5, "sql", "")
+	require.Regexp(`^\d{4} WARNING db logger/pkg/format_test.go:32 slow query ms=1.5 sql=""\n$`, buf.String())
+	buf.Reset()
+
+	logger = New(buf, Options{Format: "unknown"})
+	logger.Info("a")
+	require.Contains(buf.String(), `INFO {"message":"a"}`)
+}
+
+func TestLogfmtValue(t *testing.T) {
+	require := require.New(t)
+	require.Equal("null", logfmtValue(nil))
+	require.Equal("true", logfmtValue(true))
+	require.Equal("NaN", logfmtValue(math.NaN()))
+	require.Equal(`"a b"`, logfmtValue("a b"))
+	require.Equal(`"a\"b"`, logfmtValue(`a"b`))
+	require.Equal(`"\n"`, logfmtValue("\n"))
+	require.Equal("2018-10-13T03:05:28Z", logfmtValue(time.Date(2018, 10, 13, 3, 5, 28, 0, time.UTC)))
+	require.Equal(`"{\"a\":1}"`, logfmtValue(map[string]int{"a": 1}))
+	require.Equal("[1,2]", logfmtValue([]int{1, 2}))
+	require.Equal(`"chan int"`, logfmtValue(make(chan int)))
+	require.Equal("_a_b", logfmtKey(" a=b"))
+	require.Equal("_", logfmtKey(""))
+}
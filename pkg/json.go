@@ -0,0 +1,150 @@
+package pkg
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// maxJSONDepth bounds the nesting of the maps and slices checked by
+// jsonSafe, deeper values are written as their type.
+const maxJSONDepth = 32
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// jsonSafe returns m with the values encoding/json fails to encode replaced,
+// so a record always encodes to valid JSON. The fields of nested maps and
+// structs and the elements of slices and arrays, typed or not, are checked
+// one by one:
+//
+//   - NaN, +Inf and -Inf floats become the strings "NaN", "+Inf" and "-Inf"
+//   - other values, e.g. channels, funcs or cyclic values, become the name
+//     of their type and the error is added as the field "<key>_error"
+func jsonSafe(m map[string]interface{}, depth int) map[string]interface{} {
+	safe := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if _, err := json.Marshal(v); err == nil {
+			safe[k] = v
+			continue
+		}
+		val, err := jsonSafeValue(v, depth)
+		safe[k] = val
+		if err != nil {
+			if _, ok := m[k+"_error"]; !ok {
+				safe[k+"_error"] = err.Error()
+			}
+		}
+	}
+	return safe
+}
+
+// jsonSafeValue returns v, which encoding/json fails to encode, replaced as
+// described by jsonSafe and the error to annotate it with, if any.
+func jsonSafeValue(v interface{}, depth int) (interface{}, error) {
+	if depth >= maxJSONDepth {
+		return fmt.Sprintf("%T", v), fmt.Errorf("json: nesting deeper than %d", maxJSONDepth)
+	}
+	switch val := v.(type) {
+	case float64:
+		return jsonFloat(val), nil
+	case float32:
+		return jsonFloat(float64(val)), nil
+	case map[string]interface{}:
+		return jsonSafe(val, depth+1), nil
+	case log:
+		return jsonSafe(val, depth+1), nil
+	case []interface{}:
+		s := make([]interface{}, len(val))
+		var firstErr error
+		for i, e := range val {
+			if _, err := json.Marshal(e); err == nil {
+				s[i] = e
+				continue
+			}
+			var err error
+			if s[i], err = jsonSafeValue(e, depth+1); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("element %d: %v", i, err)
+			}
+		}
+		return s, firstErr
+	}
+	_, err := json.Marshal(v)
+	if err == nil {
+		return v, nil
+	}
+	var unsupported *json.UnsupportedValueError
+	if !errors.As(err, &unsupported) || !strings.HasPrefix(unsupported.Str, "encountered a cycle") {
+		if safe, ok, err := jsonSafeReflect(reflect.ValueOf(v), depth); ok {
+			return safe, err
+		}
+	}
+	// fmt is not used to render v, it recurses forever on cyclic values.
+	return fmt.Sprintf("%T", v), err
+}
+
+// jsonSafeReflect is jsonSafeValue for the typed maps, slices, arrays,
+// structs and pointers to them, converted to untyped maps and slices. It
+// returns false for the other values and for the types marshaling
+// themselves.
+func jsonSafeReflect(rv reflect.Value, depth int) (interface{}, bool, error) {
+	t := rv.Type()
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return nil, false, nil
+	}
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil, false, nil
+		}
+		v, err := jsonSafeValue(rv.Elem().Interface(), depth+1)
+		return v, true, err
+	case reflect.Slice, reflect.Array:
+		s := make([]interface{}, rv.Len())
+		for i := range s {
+			s[i] = rv.Index(i).Interface()
+		}
+		v, err := jsonSafeValue(s, depth)
+		return v, true, err
+	case reflect.Map:
+		if !textKey(t.Key()) {
+			return nil, false, nil
+		}
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[keyText(iter.Key())] = iter.Value().Interface()
+		}
+		return jsonSafe(m, depth+1), true, nil
+	case reflect.Struct:
+		fields := planFields(t, nil)
+		m := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			fv, ok := fieldByIndex(rv, f.index)
+			if !ok || f.omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+			m[f.name] = fv.Interface()
+		}
+		return jsonSafe(m, depth+1), true, nil
+	}
+	return nil, false, nil
+}
+
+func jsonFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return fmt.Sprint(f)
+}
@@ -0,0 +1,119 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type cyclic struct {
+	Name string
+	Next *cyclic
+}
+
+func TestJSONFallback(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{EnableJSON: true}).SetJSONLog()
+	node := &cyclic{Name: "a"}
+	node.Next = node
+	deep := map[string]interface{}{}
+	deep["self"] = deep
+
+	logger.Info("ok", 1, "ch", make(chan int), "nan", math.NaN(), "inf", float32(math.Inf(-1)),
+		"nested", map[string]interface{}{"f": func() {}, "pos": math.Inf(1), "s": "x"},
+		"list", []interface{}{1, math.NaN(), make(chan int)}, "node", node, "deep", deep)
+	line := strings.TrimSpace(buf.String())
+	require.True(json.Valid([]byte(line)), line)
+
+	var out map[string]interface{}
+	require.Nil(json.Unmarshal([]byte(line), &out))
+	require.Equal(float64(1), out["ok"])
+	require.Equal("INFO", out["level"])
+	require.Equal("chan int", out["ch"])
+	require.Equal("json: unsupported type: chan int", out["ch_error"])
+	require.Equal("NaN", out["nan"])
+	require.Equal("-Inf", out["inf"])
+	require.Nil(out["nan_error"])
+	require.Equal(map[string]interface{}{"f": out["nested"].(map[string]interface{})["f"], "f_error": "json: unsupported type: func()", "pos": "+Inf", "s": "x"}, out["nested"])
+	require.Equal([]interface{}{float64(1), "NaN", out["list"].([]interface{})[2]}, out["list"])
+	require.Equal("element 2: json: unsupported type: chan int", out["list_error"])
+	require.Equal("*pkg.cyclic", out["node"])
+	require.Contains(out["node_error"], "json: unsupported value")
+	require.NotNil(out["deep"])
+
+	buf.Reset()
+	require.Nil(logger.SetRedaction(&DefaultRedaction))
+	logger.Info("deep", deep, "password", deep)
+	require.True(json.Valid(bytes.TrimSpace(buf.Bytes())))
+	require.Contains(buf.String(), `"password":"[REDACTED]"`)
+
+	buf.Reset()
+	logfmt := New(buf, Options{EnableJSON: true, Format: "logfmt"})
+	logfmt.Info("deep", deep, "nan", math.NaN(), "f", func() {})
+	require.Contains(buf.String(), ` deep="{\"self\":{\"self\":`)
+	require.Contains(buf.String(), ` f=func()`)
+	require.Contains(buf.String(), ` nan=NaN`)
+
+	buf.Reset()
+	text := New(buf, Options{EnableJSON: true})
+	text.Info("message", "m", "f", math.Inf(1), "ch_error", "mine", "ch", make(chan int))
+	require.Contains(buf.String(), `INFO {"ch":"chan int","ch_error":"mine","f":"+Inf","message":"m"}`)
+}
+
+type cyclicHolder struct {
+	M map[string]interface{}
+}
+
+func TestJSONFallbackCyclicStruct(t *testing.T) {
+	require := require.New(t)
+	m := map[string]interface{}{}
+	m["self"] = m
+	v := cyclicHolder{M: m}
+
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{EnableJSON: true}).SetJSONLog()
+	logger.Info("v", v)
+	require.True(json.Valid(bytes.TrimSpace(buf.Bytes())), buf.String())
+	require.Contains(buf.String(), `"v":"pkg.cyclicHolder","v_error":"json: unsupported value: encountered a cycle`)
+
+	for _, format := range []string{"logfmt", "console"} {
+		buf.Reset()
+		New(buf, Options{EnableJSON: true, Format: format}).Info("message", v, "v", v)
+		require.Contains(buf.String(), "v=pkg.cyclicHolder", format)
+	}
+	buf.Reset()
+	New(buf, Options{EnableJSON: true, Format: "console"}).Info("message", v)
+	require.Contains(buf.String(), " pkg.cyclicHolder")
+}
+
+type jsonPoint struct {
+	X, Y  float64
+	Label string `json:"label,omitempty"`
+	Ch    chan int
+}
+
+func TestJSONFallbackTyped(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{EnableJSON: true}).SetJSONLog()
+
+	logger.Info("floats", []float64{1, math.NaN()}, "array", [2]float32{float32(math.Inf(1)), 2},
+		"map", map[string]float64{"a": math.Inf(-1)}, "point", &jsonPoint{X: math.NaN(), Y: 1},
+		"chans", []chan int{make(chan int)})
+	line := bytes.TrimSpace(buf.Bytes())
+	require.True(json.Valid(line), string(line))
+	require.Contains(string(line), `"array":["+Inf",2],"chans":["chan int"],"chans_error":"element 0: json: unsupported type: chan int",`)
+	require.Contains(string(line), `"floats":[1,"NaN"],`)
+	require.Contains(string(line), `"map":{"a":"-Inf"},`)
+	require.Contains(string(line), `"point":{"Ch":"chan int","Ch_error":"json: unsupported type: chan int","X":"NaN","Y":1}`)
+
+	buf.Reset()
+	logfmt := New(buf, Options{EnableJSON: true, Format: "logfmt"})
+	logfmt.Info("floats", []float64{1, math.NaN()})
+	require.Contains(buf.String(), ` floats="[1,\"NaN\"]"`)
+}
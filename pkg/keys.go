@@ -0,0 +1,229 @@
+package pkg
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Keys names the reserved keys of records, empty names keep the default
+// ones. The names apply to the json, text and logfmt formats, the console
+// format and slog handlers use their own layout.
+type Keys struct {
+	// Timestamp is the key of the record time, "timestamp".
+	Timestamp string
+	// Level is the key of the level name, "level".
+	Level string
+	// Message is the key of the message, "message".
+	Message string
+	// Values prefixes the keys of the values not passed as key value
+	// pairs, "message" for message1..N.
+	Values string
+	// Logger is the key of the logger name, "logger".
+	Logger string
+	// File is the key of the caller "path/file.go:line", "file".
+	File string
+	// GoID is the key of the goroutine ID, "goID".
+	GoID string
+	// LevelFields, if not nil, returns the fields replacing the level key.
+	LevelFields func(level uint32) map[string]interface{}
+	// FileFields, if not nil, returns the fields replacing the file key.
+	FileFields func(path string, line int) map[string]interface{}
+}
+
+// ecsLevels are the Elastic Common Schema names of the levels, those of
+// syslog.
+var ecsLevels = map[uint32]string{
+	EmergLevel:   "emergency",
+	AlertLevel:   "alert",
+	CritiLevel:   "critical",
+	ErrLevel:     "error",
+	WarningLevel: "warning",
+	NoticeLevel:  "notice",
+	InfoLevel:    "info",
+	DebugLevel:   "debug",
+}
+
+// ECSKeys follows the Elastic Common Schema.
+var ECSKeys = Keys{
+	Timestamp: "@timestamp",
+	Logger:    "log.logger",
+	GoID:      "process.thread.id",
+	LevelFields: func(level uint32) map[string]interface{} {
+		return map[string]interface{}{"log.level": ecsLevels[level]}
+	},
+	FileFields: func(path string, line int) map[string]interface{} {
+		return map[string]interface{}{"log.origin.file.name": path, "log.origin.file.line": line}
+	},
+}
+
+// gcpSeverities are the Google Cloud Logging names of the levels.
+var gcpSeverities = map[uint32]string{
+	EmergLevel:   "EMERGENCY",
+	AlertLevel:   "ALERT",
+	CritiLevel:   "CRITICAL",
+	ErrLevel:     "ERROR",
+	WarningLevel: "WARNING",
+	NoticeLevel:  "NOTICE",
+	InfoLevel:    "INFO",
+	DebugLevel:   "DEBUG",
+}
+
+// GCPKeys follows the structured logging of Google Cloud Logging.
+var GCPKeys = Keys{
+	Timestamp: "time",
+	LevelFields: func(level uint32) map[string]interface{} {
+		return map[string]interface{}{"severity": gcpSeverities[level]}
+	},
+	FileFields: func(path string, line int) map[string]interface{} {
+		return map[string]interface{}{"logging.googleapis.com/sourceLocation": map[string]interface{}{
+			"file": path,
+			"line": strconv.Itoa(line),
+		}}
+	},
+}
+
+// otelSeverities are the OpenTelemetry severity numbers of the levels.
+var otelSeverities = map[uint32]int{
+	EmergLevel:   21,
+	AlertLevel:   19,
+	CritiLevel:   18,
+	ErrLevel:     17,
+	WarningLevel: 13,
+	NoticeLevel:  10,
+	InfoLevel:    9,
+	DebugLevel:   5,
+}
+
+// OTelKeys follows the OpenTelemetry log data model.
+var OTelKeys = Keys{
+	Timestamp: "Timestamp",
+	Message:   "Body",
+	Logger:    "InstrumentationScope",
+	GoID:      "thread.id",
+	LevelFields: func(level uint32) map[string]interface{} {
+		return map[string]interface{}{"SeverityText": levels[level], "SeverityNumber": otelSeverities[level]}
+	},
+	FileFields: func(path string, line int) map[string]interface{} {
+		return map[string]interface{}{"code.filepath": path, "code.lineno": line}
+	},
+}
+
+// keySchema is the compiled Keys of a logger, nil if all are the defaults.
+type keySchema struct {
+	names       map[string]string
+	values      string
+	levelFields func(level uint32) map[string]interface{}
+	fileFields  func(path string, line int) map[string]interface{}
+	// outputs are the names replacing the reserved keys.
+	outputs map[string][]string
+}
+
+func newKeySchema(k Keys) *keySchema {
+	s := &keySchema{names: map[string]string{}, values: k.Values,
+		levelFields: k.LevelFields, fileFields: k.FileFields}
+	for key, name := range map[string]string{
+		timestampKey: k.Timestamp,
+		levelKey:     k.Level,
+		message:      k.Message,
+		loggerName:   k.Logger,
+		file:         k.File,
+		goID:         k.GoID,
+	} {
+		if name != "" && name != key {
+			s.names[key] = name
+		}
+	}
+	if len(s.names) == 0 && (s.values == "" || s.values == message) &&
+		s.levelFields == nil && s.fileFields == nil {
+		return nil
+	}
+	s.outputs = map[string][]string{}
+	for key, name := range s.names {
+		s.outputs[key] = []string{name}
+	}
+	if s.levelFields != nil {
+		s.outputs[levelKey] = appendSorted(nil, s.levelFields(InfoLevel))
+	}
+	if s.fileFields != nil {
+		s.outputs[file] = appendSorted(nil, s.fileFields("", 0))
+	}
+	return s
+}
+
+// outputNames returns the reserved key k followed by the names replacing it.
+func (s *keySchema) outputNames(k string) []string {
+	if s == nil {
+		return []string{k}
+	}
+	return append([]string{k}, s.outputs[k]...)
+}
+
+// renamed returns the fields replacing the reserved key k of value v, nil
+// if k is not renamed.
+func (s *keySchema) renamed(k string, v interface{}, level uint32) map[string]interface{} {
+	switch {
+	case k == levelKey && s.levelFields != nil:
+		return s.levelFields(level)
+	case k == file && s.fileFields != nil:
+		if str, ok := v.(string); ok {
+			if i := strings.LastIndexByte(str, ':'); i >= 0 {
+				line, _ := strconv.Atoi(str[i+1:])
+				return s.fileFields(str[:i], line)
+			}
+		}
+	}
+	if name, ok := s.names[k]; ok {
+		return map[string]interface{}{name: v}
+	}
+	if s.values != "" && strings.HasPrefix(k, message) && len(k) > len(message) {
+		if _, err := strconv.Atoi(k[len(message):]); err == nil {
+			return map[string]interface{}{s.values + k[len(message):]: v}
+		}
+	}
+	return nil
+}
+
+// renameKeys replaces the reserved keys of m by their names in the logger's
+// schema, in place in order if not nil.
+func (a *Logger) renameKeys(m log, order []string, level uint32) []string {
+	s := a.keys
+	if s == nil {
+		return order
+	}
+	keys := order
+	if keys == nil {
+		keys = make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	var renamed []string
+	if order != nil {
+		renamed = make([]string, 0, len(order)+2)
+	}
+	for _, k := range keys {
+		fields := s.renamed(k, m[k], level)
+		if fields == nil {
+			if order != nil {
+				renamed = append(renamed, k)
+			}
+			continue
+		}
+		delete(m, k)
+		for name, v := range fields {
+			m[name] = v
+		}
+		if order != nil {
+			renamed = appendSorted(renamed, fields)
+		}
+	}
+	return renamed
+}
+
+// header returns the timestamp and level fields of a record in the
+// logger's schema, in order.
+func (a *Logger) header(ts string, level uint32) ([]string, map[string]interface{}) {
+	m := log{timestampKey: ts, levelKey: levels[level]}
+	order := a.renameKeys(m, []string{timestampKey, levelKey}, level)
+	return order, m
+}
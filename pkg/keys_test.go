@@ -0,0 +1,61 @@
+package pkg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeys(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	keys := Keys{Timestamp: "ts", Level: "lvl", Message: "msg", Values: "arg", Logger: "name", File: "caller", GoID: "gid"}
+	opt := Options{EnableJSON: true, EnableFileLine: true, EnableGoID: true, Deterministic: true, Keys: keys}
+	logger := New(buf, opt).SetJSONLog()
+	logger.Named("db").Info("message", "hi", "user", "u1")
+	require.Regexp(`^\{"caller":"logger/pkg/keys_test.go:\d+","gid":1,"lvl":"INFO","msg":"hi","name":"db","ts":"2000-01-01T00:00:00Z","user":"u1"\}\n$`, buf.String())
+	buf.Reset()
+
+	logger.Info("a", "b", "c")
+	require.Contains(buf.String(), `"arg1":"a","arg2":"b","arg3":"c"`)
+	buf.Reset()
+
+	opt.FieldOrder = true
+	New(buf, opt).SetJSONLog().Info("user", "u1", "message", "hi")
+	require.Regexp(`^\{"ts":"2000-01-01T00:00:00Z","lvl":"INFO","caller":"[^"]+","gid":1,"msg":"hi","user":"u1"\}\n$`, buf.String())
+	buf.Reset()
+
+	New(buf, opt).Info("message", "hi")
+	require.Regexp(`^\[2000-01-01T00:00:00Z\] INFO \{"caller":"[^"]+","gid":1,"msg":"hi"\}\n$`, buf.String())
+	buf.Reset()
+
+	opt.Format = "logfmt"
+	New(buf, opt).Info("message", "hi")
+	require.Regexp(`^ts=2000-01-01T00:00:00Z lvl=INFO caller=logger/pkg/keys_test.go:\d+ gid=1 msg=hi\n$`, buf.String())
+	buf.Reset()
+
+	preset := Options{EnableJSON: true, EnableFileLine: true, Deterministic: true, FieldOrder: true, Format: "json"}
+	preset.Keys = GCPKeys
+	New(buf, preset).Err("message", "failed")
+	require.Regexp(`^\{"time":"2000-01-01T00:00:00Z","severity":"ERROR","logging.googleapis.com/sourceLocation":\{"file":"logger/pkg/keys_test.go","line":"\d+"\},"message":"failed"\}\n$`, buf.String())
+	buf.Reset()
+
+	preset.Keys = ECSKeys
+	New(buf, preset).Named("db").Warning("message", "slow")
+	require.Regexp(`^\{"@timestamp":"2000-01-01T00:00:00Z","log.level":"warning","log.logger":"db","log.origin.file.line":\d+,"log.origin.file.name":"logger/pkg/keys_test.go","message":"slow"\}\n$`, buf.String())
+	buf.Reset()
+	New(buf, preset).Err("message", "failed")
+	require.Contains(buf.String(), `"log.level":"error"`)
+	buf.Reset()
+	New(buf, preset).Crit("message", "failed")
+	require.Contains(buf.String(), `"log.level":"critical"`)
+	buf.Reset()
+
+	preset.Keys = OTelKeys
+	preset.Format = "logfmt"
+	New(buf, preset).Notice("message", "up")
+	require.Regexp(`^Timestamp=2000-01-01T00:00:00Z SeverityNumber=10 SeverityText=NOTICE code.filepath=logger/pkg/keys_test.go code.lineno=\d+ Body=up\n$`, buf.String())
+
+	require.Nil(newKeySchema(Keys{Message: "message", Values: "message"}))
+}
@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testValuer struct{}
+
+func (testValuer) LogValue() interface{} {
+	return testValuer{}
+}
+
+func TestLazy(t *testing.T) {
+	require := require.New(t)
+	buf1, buf2 := new(bytes.Buffer), new(bytes.Buffer)
+	logger := New(io.MultiWriter(buf1, buf2), Options{EnableJSON: true})
+	calls := 0
+	expensive := Lazy(func() interface{} {
+		calls++
+		return map[string]interface{}{"items": testItems{"a"}, "err": errors.New("x")}
+	})
+
+	logger.Debug("state", expensive)
+	require.Equal(0, calls)
+	require.Empty(buf1.String())
+
+	logger.Info("state", expensive)
+	require.Equal(1, calls)
+	require.Contains(buf1.String(), `{"state":{"err":{},"items":["a"]}}`)
+	require.Equal(buf1.String(), buf2.String())
+	buf1.Reset()
+
+	bound := logger.Named("bound").SetFields("n", Lazy(func() interface{} { calls++; return calls }))
+	bound.Info("a", 1)
+	bound.Info("a", 2)
+	require.Equal(3, calls)
+	require.Contains(buf1.String(), `{"a":2,"logger":"bound","n":3}`)
+	buf1.Reset()
+
+	logger.Info("err", Lazy(func() interface{} { return errors.New("boom") }), "loop", testValuer{},
+		"nested", Lazy(func() interface{} { return Lazy(func() interface{} { return "deep" }) }))
+	require.Contains(buf1.String(), `{"err":"boom","loop":null,"nested":"deep"}`)
+	buf1.Reset()
+
+	text := New(buf1)
+	text.Info("state ", Lazy(func() interface{} { return "ok" }))
+	require.Contains(buf1.String(), `{"message":"state ok"}`)
+}
@@ -0,0 +1,201 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// levelHandler serves the levels of a logger tree over HTTP.
+type levelHandler struct {
+	logger  *Logger
+	mu      sync.Mutex
+	reverts map[string]*revert
+}
+
+// revert restores the level a logger had before a temporary change.
+type revert struct {
+	timer *time.Timer
+	level uint32
+	at    time.Time
+}
+
+// levelRequest is the JSON body of a PUT, the fields can also be passed
+// as query parameters.
+type levelRequest struct {
+	Logger string          `json:"logger"`
+	Level  json.RawMessage `json:"level"`
+	Revert string          `json:"revert"`
+}
+
+type levelState struct {
+	Name      string     `json:"name"`
+	Level     string     `json:"level"`
+	Value     uint32     `json:"value"`
+	Inherited bool       `json:"inherited"`
+	RevertAt  *time.Time `json:"revert_at,omitempty"`
+}
+
+// LevelHandler returns an http.Handler to read and change the levels of
+// the tree of a at runtime, answering with the JSON list of levels.
+//
+//	GET /?logger=payments
+//	PUT /?logger=payments&level=debug&revert=10m
+//	PUT / {"logger":"payments","level":7,"revert":"10m"}
+//
+// The logger parameter is the name of a logger created with Named, empty
+// for the root, GET without it lists every logger. Levels are names as
+// accepted by ParseLevel or numbers, "inherit" resets a named logger to
+// the level of its parent. With revert, the previous level is restored
+// once the duration has elapsed.
+func (a *Logger) LevelHandler() http.Handler {
+	return &levelHandler{logger: a, reverts: map[string]*revert{}}
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		_, single := q["logger"]
+		name := q.Get("logger")
+		if single && h.logger.Lookup(name) == nil {
+			h.error(w, http.StatusNotFound, fmt.Errorf("logger %q not found", name))
+			return
+		}
+		h.states(w, name, single)
+	case http.MethodPut:
+		req, err := readLevelRequest(r)
+		if err != nil {
+			h.error(w, http.StatusBadRequest, err)
+			return
+		}
+		status, err := h.apply(req)
+		if err != nil {
+			h.error(w, status, err)
+			return
+		}
+		h.states(w, req.Logger, true)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		h.error(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	}
+}
+
+func readLevelRequest(r *http.Request) (*levelRequest, error) {
+	req := &levelRequest{}
+	if r.Body != nil {
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, 1<<16))
+		if err != nil {
+			return nil, err
+		}
+		if len(strings.TrimSpace(string(body))) > 0 {
+			if err := json.Unmarshal(body, req); err != nil {
+				return nil, fmt.Errorf("invalid body: %v", err)
+			}
+		}
+	}
+	q := r.URL.Query()
+	if _, ok := q["logger"]; ok {
+		req.Logger = q.Get("logger")
+	}
+	if _, ok := q["level"]; ok {
+		req.Level, _ = json.Marshal(q.Get("level"))
+	}
+	if _, ok := q["revert"]; ok {
+		req.Revert = q.Get("revert")
+	}
+	if len(req.Level) == 0 {
+		return nil, fmt.Errorf("missing level")
+	}
+	return req, nil
+}
+
+// apply changes the level of the requested logger and schedules its revert.
+func (h *levelHandler) apply(req *levelRequest) (int, error) {
+	l := h.logger.Lookup(req.Logger)
+	if l == nil {
+		return http.StatusNotFound, fmt.Errorf("logger %q not found", req.Logger)
+	}
+	var name string
+	if err := json.Unmarshal(req.Level, &name); err != nil {
+		name = string(req.Level)
+	}
+	level, ok := lookupLevel(name)
+	if name == "inherit" {
+		if l.parent == nil {
+			return http.StatusBadRequest, fmt.Errorf("the root logger can not inherit a level")
+		}
+		level, ok = levelInherit, true
+	}
+	if !ok {
+		return http.StatusBadRequest, fmt.Errorf("invalid level %q", name)
+	}
+	var after time.Duration
+	if req.Revert != "" {
+		d, err := time.ParseDuration(req.Revert)
+		if err != nil || d <= 0 {
+			return http.StatusBadRequest, fmt.Errorf("invalid revert %q", req.Revert)
+		}
+		after = d
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	prev := atomic.LoadUint32(&l.ulevel)
+	if rv := h.reverts[l.name]; rv != nil {
+		// Keep the level from before the first temporary change.
+		rv.timer.Stop()
+		prev = rv.level
+		delete(h.reverts, l.name)
+	}
+	atomic.StoreUint32(&l.ulevel, level)
+	if after > 0 {
+		rv := &revert{level: prev, at: time.Now().Add(after)}
+		rv.timer = time.AfterFunc(after, func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if h.reverts[l.name] == rv {
+				atomic.StoreUint32(&l.ulevel, rv.level)
+				delete(h.reverts, l.name)
+			}
+		})
+		h.reverts[l.name] = rv
+	}
+	return http.StatusOK, nil
+}
+
+func (h *levelHandler) states(w http.ResponseWriter, name string, single bool) {
+	h.mu.Lock()
+	list := []levelState{}
+	for _, ll := range h.logger.Loggers() {
+		if single && ll.Name != name {
+			continue
+		}
+		state := levelState{
+			Name:      ll.Name,
+			Level:     levels[ll.Level],
+			Value:     ll.Level,
+			Inherited: ll.Inherited,
+		}
+		if rv := h.reverts[ll.Name]; rv != nil {
+			at := rv.at.UTC()
+			state.RevertAt = &at
+		}
+		list = append(list, state)
+	}
+	h.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"loggers": list})
+}
+
+func (h *levelHandler) error(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,83 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelHandler(t *testing.T) {
+	require := require.New(t)
+	root := New(new(bytes.Buffer))
+	stripe := root.Named("payments.stripe")
+	h := root.LevelHandler()
+
+	do := func(method, target, body string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+		res := map[string]interface{}{}
+		require.Nil(json.Unmarshal(w.Body.Bytes(), &res))
+		return w.Code, res
+	}
+
+	code, res := do(http.MethodGet, "/", "")
+	require.Equal(http.StatusOK, code)
+	require.Len(res["loggers"], 3)
+
+	code, res = do(http.MethodGet, "/?logger=payments.stripe", "")
+	require.Equal(http.StatusOK, code)
+	require.Equal([]interface{}{map[string]interface{}{
+		"name": "payments.stripe", "level": "INFO", "value": float64(6), "inherited": true,
+	}}, res["loggers"])
+
+	code, _ = do(http.MethodPut, "/?logger=payments&level=debug", "")
+	require.Equal(http.StatusOK, code)
+	require.Equal(DebugLevel, stripe.Level())
+
+	code, _ = do(http.MethodPut, "/", `{"logger":"payments.stripe","level":4}`)
+	require.Equal(http.StatusOK, code)
+	require.Equal(WarningLevel, stripe.Level())
+
+	code, _ = do(http.MethodPut, "/?logger=payments.stripe&level=inherit", "")
+	require.Equal(http.StatusOK, code)
+	require.Equal(DebugLevel, stripe.Level())
+
+	code, res = do(http.MethodPut, "/", `{"level":"err","revert":"50ms"}`)
+	require.Equal(http.StatusOK, code)
+	require.Equal(ErrLevel, root.Level())
+	require.NotEmpty(res["loggers"].([]interface{})[0].(map[string]interface{})["revert_at"])
+	code, _ = do(http.MethodPut, "/?level=crit&revert=50ms", "")
+	require.Equal(http.StatusOK, code)
+	require.Equal(CritiLevel, root.Level())
+	for i := 0; i < 100 && root.Level() != InfoLevel; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(InfoLevel, root.Level())
+
+	code, res = do(http.MethodPut, "/?level=verbose", "")
+	require.Equal(http.StatusBadRequest, code)
+	require.Equal(`invalid level "verbose"`, res["error"])
+	code, _ = do(http.MethodPut, "/?level=8", "")
+	require.Equal(http.StatusBadRequest, code)
+	code, _ = do(http.MethodPut, "/?level=inherit", "")
+	require.Equal(http.StatusBadRequest, code)
+	code, _ = do(http.MethodPut, "/?level=debug&revert=soon", "")
+	require.Equal(http.StatusBadRequest, code)
+	code, _ = do(http.MethodPut, "/", `{"level":`)
+	require.Equal(http.StatusBadRequest, code)
+	code, _ = do(http.MethodPut, "/", "")
+	require.Equal(http.StatusBadRequest, code)
+	code, _ = do(http.MethodPut, "/?logger=orders&level=debug", "")
+	require.Equal(http.StatusNotFound, code)
+	code, _ = do(http.MethodGet, "/?logger=orders", "")
+	require.Equal(http.StatusNotFound, code)
+	code, _ = do(http.MethodDelete, "/", "")
+	require.Equal(http.StatusMethodNotAllowed, code)
+	require.Equal(InfoLevel, root.Level())
+}
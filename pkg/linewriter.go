@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// defaultMaxLineSize is the length from which a LineWriter splits lines.
+const defaultMaxLineSize = 64 * 1024
+
+// LineWriter is an io.WriteCloser writing a record for every line written
+// to it, for example the output of a subprocess. Partial lines are kept
+// until their end is written or the writer is closed, and lines longer
+// than the maximum line size are split in several records.
+type LineWriter struct {
+	logger    *Logger
+	level     uint32
+	kv        []interface{}
+	mu        sync.Mutex
+	buf       []byte
+	maxLine   int
+	mergeJSON bool
+}
+
+// Writer returns a LineWriter writing records at level with the fields kv
+// and the line as message.
+func (a *Logger) Writer(level uint32, kv ...interface{}) *LineWriter {
+	return &LineWriter{
+		logger:  a,
+		level:   level,
+		kv:      kv,
+		maxLine: defaultMaxLineSize,
+	}
+}
+
+// SetMaxLineSize sets the length from which lines are split, 64KiB by default.
+func (w *LineWriter) SetMaxLineSize(n int) *LineWriter {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if n > 0 {
+		w.maxLine = n
+	}
+	return w
+}
+
+// SetMergeJSON makes lines holding a JSON object write a record with the
+// fields of the object instead of the line as message.
+func (w *LineWriter) SetMergeJSON(enable bool) *LineWriter {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.mergeJSON = enable
+	return w
+}
+
+// Write ...
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 || i > w.maxLine {
+			if len(w.buf) < w.maxLine {
+				break
+			}
+			i = w.maxLine
+			w.write(w.buf[:i])
+			w.buf = w.buf[i:]
+			continue
+		}
+		w.write(bytes.TrimSuffix(w.buf[:i], []byte{'\r'}))
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// Close writes the pending partial line.
+func (w *LineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.write(w.buf)
+		w.buf = nil
+	}
+	return nil
+}
+
+func (w *LineWriter) write(line []byte) {
+	a := w.logger
+	if !a.checkLevelFile(w.level, "") {
+		return
+	}
+	if !a.enableJSON {
+		a.Output(a.now(), w.level, fmt.Sprint(append(w.kv[:len(w.kv):len(w.kv)], string(line))...))
+		return
+	}
+	m := log{}
+	if len(w.kv) > 0 {
+		kvLog(m, w.kv, nil)
+	}
+	var obj map[string]interface{}
+	if w.mergeJSON && len(bytes.TrimSpace(line)) > 0 && bytes.TrimSpace(line)[0] == '{' &&
+		json.Unmarshal(line, &obj) == nil {
+		for k, v := range obj {
+			m[k] = v
+		}
+	} else {
+		m[message] = string(line)
+	}
+	a.Output(a.now(), w.level, m)
+}
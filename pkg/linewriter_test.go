@@ -0,0 +1,58 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineWriter(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{EnableJSON: true, EnableFileLine: true})
+	var w io.WriteCloser = logger.Writer(WarningLevel, "cmd", "make")
+
+	n, err := w.Write([]byte("first\r\nsec"))
+	require.Nil(err)
+	require.Equal(10, n)
+	require.Equal(1, strings.Count(buf.String(), "\n"))
+	require.Contains(buf.String(), `WARNING {"cmd":"make","message":"first"}`)
+	buf.Reset()
+
+	fmt.Fprint(w, "ond\n\nthird")
+	require.Equal(2, strings.Count(buf.String(), "\n"))
+	require.Contains(buf.String(), `{"cmd":"make","message":"second"}`)
+	require.Contains(buf.String(), `{"cmd":"make","message":""}`)
+	buf.Reset()
+
+	require.Nil(w.Close())
+	require.Contains(buf.String(), `{"cmd":"make","message":"third"}`)
+	buf.Reset()
+	require.Nil(w.Close())
+	require.Empty(buf.String())
+
+	lw := logger.Writer(InfoLevel).SetMaxLineSize(4)
+	lw.Write([]byte("abcdefghij\n"))
+	require.Equal(3, strings.Count(buf.String(), "\n"))
+	require.Contains(buf.String(), `{"message":"abcd"}`)
+	require.Contains(buf.String(), `{"message":"efgh"}`)
+	require.Contains(buf.String(), `{"message":"ij"}`)
+	buf.Reset()
+
+	lw = logger.Writer(InfoLevel, "cmd", "make").SetMergeJSON(true)
+	lw.Write([]byte(`{"status":200,"path":"/"}` + "\n" + `{not json` + "\n"))
+	require.Contains(buf.String(), `{"cmd":"make","path":"/","status":200}`)
+	require.Contains(buf.String(), `{"cmd":"make","message":"{not json"}`)
+	buf.Reset()
+
+	logger.Writer(DebugLevel).Write([]byte("hidden\n"))
+	require.Empty(buf.String())
+
+	text := New(buf)
+	text.Writer(ErrLevel, "make: ").Write([]byte("failed\n"))
+	require.Contains(buf.String(), `ERR {"message":"make: failed"}`)
+}
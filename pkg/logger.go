@@ -49,11 +49,17 @@ var (
 		5: "NOTICE",
 		6: "INFO",
 		7: "DEBUG"}
-	message = "message"
-	file    = "file"
-	goID    = "goID"
+	message      = "message"
+	file         = "file"
+	goID         = "goID"
+	loggerName   = "logger"
+	timestampKey = "timestamp"
+	levelKey     = "level"
 )
 
+// defaultSkip is the caller skip of loggers called directly by user code.
+const defaultSkip = 3
+
 // Options ...
 type Options struct {
 	LogFormat      string
@@ -62,15 +68,61 @@ type Options struct {
 	EnableFileLine bool
 	EnableGoID     bool
 	Skip           int
+	// Level is the initial level name or number, InfoLevel if empty.
+	Level string
+	// Format is "text" (the default), "json", "logfmt" or "console".
+	Format string
+	// Out replaces the writer passed to New if not nil.
+	Out io.Writer
+	// Clock returns the time of the records, time.Now if nil.
+	Clock func() time.Time
+	// GoroutineID returns the goroutine ID of the records, the package
+	// function GoroutineID if nil.
+	GoroutineID func() uint64
+	// Deterministic makes the output stable for golden files: records
+	// without a Clock are stamped 2000-01-01T00:00:00Z and goroutines
+	// without a GoroutineID are numbered 1, 2... in the order they first
+	// write a record.
+	Deterministic bool
+	// FieldOrder writes the header keys first, then the bound fields in
+	// the order they were set, then the call-site fields in argument
+	// order, instead of all the fields sorted by key.
+	FieldOrder bool
+	// HeaderKeys are the keys FieldOrder writes first, by default
+	// timestamp, level, logger, file, goID and message.
+	HeaderKeys []string
+	// Keys renames the reserved keys, see ECSKeys, GCPKeys and OTelKeys.
+	Keys Keys
+	// Collisions is what happens to the fields named like the keys the
+	// logger adds, timestamp, level, logger, file and goID or their names
+	// in Keys: "overwrite" (the default) replaces them, except file which
+	// the field replaces, "prefix" renames them fields.<key>, "nest" moves
+	// them to the "fields" object and "suffix" renames them <key>_1.
+	Collisions string
+	// OnCollision, if not nil, is called with the key of such fields before
+	// Collisions applies, e.g. to report them in tests.
+	OnCollision func(key string)
+	// PrintfArgs makes the *f methods also record the format string as
+	// "format" and the args as arg0..argN, errors as objects of their
+	// message and type.
+	PrintfArgs bool
 }
 
 // New create logger instance
 func New(w io.Writer, options ...Options) *Logger {
 	logger := &Logger{
-		Out: w,
-		tf:  "2006-01-02T15:04:05.999Z",
-		lf:  "[%s] %s %s",
-	}
+		Out:        w,
+		mu:         new(sync.Mutex),
+		tf:         "2006-01-02T15:04:05.999Z",
+		lf:         "[%s] %s %s",
+		skip:       defaultSkip,
+		registry:   &registry{loggers: map[string]*Logger{}},
+		clock:      time.Now,
+		goid:       GoroutineID,
+		headerKeys: defaultHeaderKeys,
+	}
+	logger.root = logger
+	logger.registry.loggers[""] = logger
 	atomic.StoreUint32(&logger.ulevel, InfoLevel)
 	if len(options) == 0 {
 		return logger
@@ -81,7 +133,7 @@ func New(w io.Writer, options ...Options) *Logger {
 	logger.enableGoID = opt.EnableGoID
 	logger.skip = opt.Skip
 	if logger.skip == 0 {
-		logger.skip = 3
+		logger.skip = defaultSkip
 	}
 	if opt.TimeFormat != "" {
 		logger.tf = opt.TimeFormat
@@ -89,31 +141,130 @@ func New(w io.Writer, options ...Options) *Logger {
 	if opt.LogFormat != "" {
 		logger.lf = opt.LogFormat
 	}
+	if ulevel, ok := lookupLevel(opt.Level); ok {
+		atomic.StoreUint32(&logger.ulevel, ulevel)
+	}
+	logger.format = formats[strings.ToLower(opt.Format)]
+	if opt.Out != nil {
+		logger.Out = opt.Out
+	}
+	logger.deterministic = opt.Deterministic
+	if opt.Deterministic {
+		logger.clock = deterministicClock
+		logger.goid = newGoroutineSeq().id
+	}
+	if opt.Clock != nil {
+		logger.clock = opt.Clock
+	}
+	if opt.GoroutineID != nil {
+		logger.goid = opt.GoroutineID
+	}
+	logger.fieldOrder = opt.FieldOrder
+	if opt.HeaderKeys != nil {
+		logger.headerKeys = opt.HeaderKeys
+	}
+	logger.keys = newKeySchema(opt.Keys)
+	logger.collision = collisions[strings.ToLower(opt.Collisions)]
+	logger.onCollision = opt.OnCollision
+	logger.printfArgs = opt.PrintfArgs
 	return logger
 }
 
+// sink receives the records of the loggers delivering them elsewhere
+// instead of writing them, see NewSlogLogger.
+type sink interface {
+	output(t time.Time, level uint32, pc uintptr, m log, order []string) error
+}
+
 // Logger ...
 type Logger struct {
-	Out            io.Writer
-	mu             sync.Mutex
-	tf, lf         string
-	enableJSON     bool
-	ulevel         uint32
-	enableFileLine bool
-	enableGoID     bool
-	skip           int
-	json           bool
+	Out              io.Writer
+	mu               *sync.Mutex
+	tf, lf           string
+	enableJSON       bool
+	ulevel           uint32
+	enableFileLine   bool
+	enableGoID       bool
+	skip             int
+	format           uint8
+	sites            sync.Map
+	vmodule          atomic.Value
+	redactor         atomic.Value
+	fields           atomic.Value
+	sink             sink
+	name             string
+	parent, root     *Logger
+	registry         *registry
+	clock            func() time.Time
+	goid             func() uint64
+	deterministic    bool
+	fieldOrder       bool
+	headerKeys       []string
+	keys             *keySchema
+	collision        uint8
+	onCollision      func(key string)
+	printfArgs       bool
 }
 
 func (a *Logger) checkLogLevel(level uint32) bool {
-	val := atomic.LoadUint32(&a.ulevel)
-	return level <= val
+	return a.checkLevelSkip(level, a.skip+1)
+}
+
+// checkLevelSkip is checkLogLevel for the call site skip frames up, as
+// GetCaller(skip) would find it from the caller of checkLevelSkip.
+func (a *Logger) checkLevelSkip(level uint32, skip int) bool {
+	val := a.Level()
+	vm, _ := a.root.vmodule.Load().(*vmodule)
+	if vm == nil {
+		return level <= val
+	}
+	if level <= val && level <= vm.min {
+		return true
+	}
+	if level > val && level > vm.max {
+		return false
+	}
+	// checkLevelSkip sits one frame closer to the call site than GetCaller in magic.
+	return level <= vm.level(callerPC(skip-1), val)
+}
+
+// checkLevelPC is checkLogLevel for the code at pc. If pc is 0, vmodule
+// rules are assumed to match.
+func (a *Logger) checkLevelPC(level uint32, pc uintptr) bool {
+	val := a.Level()
+	vm, _ := a.root.vmodule.Load().(*vmodule)
+	switch {
+	case vm == nil:
+		return level <= val
+	case pc == 0:
+		return level <= val || level <= vm.max
+	}
+	return level <= vm.level(pc, val)
+}
+
+// checkLevelFile is checkLogLevel for the code in file. If file is empty,
+// vmodule rules are assumed to match.
+func (a *Logger) checkLevelFile(level uint32, file string) bool {
+	val := a.Level()
+	vm, _ := a.root.vmodule.Load().(*vmodule)
+	switch {
+	case vm == nil:
+		return level <= val
+	case file == "":
+		return level <= val || level <= vm.max
+	}
+	return level <= vm.fileLevel(file, val)
 }
 
-// Level ...
+// Level returns the logger's level, named loggers without a level of
+// their own return the level of their nearest configured ancestor.
 func (a *Logger) Level() uint32 {
-	val := atomic.LoadUint32(&a.ulevel)
-	return val
+	for l := a; l != nil; l = l.parent {
+		if val := atomic.LoadUint32(&l.ulevel); val != levelInherit {
+			return val
+		}
+	}
+	return InfoLevel
 }
 
 // SetLevel set the logger's log level
@@ -135,22 +286,60 @@ func (a *Logger) SetLoggerLevel(level string) *Logger {
 	return a
 }
 
-// SetJSONLog set the logger writing JSON string log.
+// SetJSONLog set the logger writing JSON string log, with the named
+// loggers already created below it.
 func (a *Logger) SetJSONLog() *Logger {
 	a.mu.Lock()
 	defer a.mu.Unlock()
-	a.json = true
+	a.format = formatJSON
+	r := a.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, l := range r.loggers {
+		if a.name == "" || strings.HasPrefix(name, a.name+".") {
+			l.format = formatJSON
+		}
+	}
 	return a
 }
 
 // Output ...
 func (a *Logger) Output(t time.Time, level uint32, v interface{}) (err error) {
+	return a.output(t, level, v, a.skip+1)
+}
+
+// output is Output for the call site skip frames up, as runtime.Callers
+// finds it from output: only sinks need its PC.
+func (a *Logger) output(t time.Time, level uint32, v interface{}, skip int) (err error) {
 	logObj := format2Log(v)
-	if a.json {
-		logObj["timestamp"] = t.Format(a.tf)
-		logObj["level"] = levels[level]
+	var callKeys []string
+	if o, ok := v.(*orderedLog); ok {
+		callKeys = o.keys
+	}
+	a.addFields(logObj)
+	if a.checkCollisions() {
+		a.collide(logObj, callKeys, a.reservedKeys())
+	}
+	if a.name != "" {
+		logObj[loggerName] = a.name
+	}
+	if a.enableGoID {
+		logObj[goID] = a.goid()
+	}
+	a.encodeValues(logObj)
+	a.redact(logObj)
+	if a.sink != nil {
+		var pcs [1]uintptr
+		runtime.Callers(skip, pcs[:])
+		return a.sink.output(t, level, pcs[0], logObj, a.keyOrder(logObj, callKeys))
+	}
+	switch a.format {
+	case formatJSON:
+		logObj[timestampKey] = t.Format(a.tf)
+		logObj[levelKey] = levels[level]
+		order := a.renameKeys(logObj, a.keyOrder(logObj, callKeys), level)
 
-		str := a.jsonFormat(logObj)
+		str := a.jsonFormat(logObj, order)
 
 		a.mu.Lock()
 		defer a.mu.Unlock()
@@ -158,8 +347,22 @@ func (a *Logger) Output(t time.Time, level uint32, v interface{}) (err error) {
 		if err == nil {
 			a.Out.Write([]byte{'\n'})
 		}
-	} else {
-		str := a.jsonFormat(logObj)
+	case formatLogfmt, formatConsole:
+		var str string
+		order := a.keyOrder(logObj, callKeys)
+		if a.format == formatLogfmt {
+			order = a.renameKeys(logObj, order, level)
+			str = a.logfmtFormat(t, level, logObj, order)
+		} else {
+			str = a.consoleFormat(t, level, logObj, order)
+		}
+
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		_, err = fmt.Fprintln(a.Out, str)
+	default:
+		order := a.renameKeys(logObj, a.keyOrder(logObj, callKeys), level)
+		str := a.jsonFormat(logObj, order)
 
 		a.mu.Lock()
 		defer a.mu.Unlock()
@@ -177,28 +380,28 @@ func (a *Logger) Output(t time.Time, level uint32, v interface{}) (err error) {
 // Debug ...
 func (a *Logger) Debug(kv ...interface{}) {
 	if a.checkLogLevel(DebugLevel) {
-		a.Output(time.Now().UTC(), DebugLevel, a.magic(kv...))
+		a.Output(a.now(), DebugLevel, a.magic(kv...))
 	}
 }
 
 // Info ...
 func (a *Logger) Info(kv ...interface{}) {
 	if a.checkLogLevel(InfoLevel) {
-		a.Output(time.Now().UTC(), InfoLevel, a.magic(kv...))
+		a.Output(a.now(), InfoLevel, a.magic(kv...))
 	}
 }
 
 // Notice ...
 func (a *Logger) Notice(kv ...interface{}) {
 	if a.checkLogLevel(NoticeLevel) {
-		a.Output(time.Now().UTC(), NoticeLevel, a.magic(kv...))
+		a.Output(a.now(), NoticeLevel, a.magic(kv...))
 	}
 }
 
 // Warning ...
 func (a *Logger) Warning(kv ...interface{}) {
 	if a.checkLogLevel(WarningLevel) {
-		a.Output(time.Now().UTC(), WarningLevel, a.magic(kv...))
+		a.Output(a.now(), WarningLevel, a.magic(kv...))
 	}
 }
 
@@ -217,7 +420,7 @@ func (a *Logger) NotNil(err interface{}, kv ...interface{}) bool {
 		l = append(l, p)
 	}
 	if a.checkLogLevel(ErrLevel) {
-		a.Output(time.Now().UTC(), ErrLevel, a.magic(l...))
+		a.Output(a.now(), ErrLevel, a.magic(l...))
 	}
 	return true
 }
@@ -225,41 +428,71 @@ func (a *Logger) NotNil(err interface{}, kv ...interface{}) bool {
 // Err ...
 func (a *Logger) Err(kv ...interface{}) {
 	if a.checkLogLevel(ErrLevel) {
-		a.Output(time.Now().UTC(), ErrLevel, a.magic(kv...))
+		a.Output(a.now(), ErrLevel, a.magic(kv...))
 	}
 }
 
 // Crit ...
 func (a *Logger) Crit(kv ...interface{}) {
 	if a.checkLogLevel(CritiLevel) {
-		a.Output(time.Now().UTC(), CritiLevel, a.magic(kv...))
+		a.Output(a.now(), CritiLevel, a.magic(kv...))
 	}
 }
 
 // Alert ...
 func (a *Logger) Alert(kv ...interface{}) {
 	if a.checkLogLevel(AlertLevel) {
-		a.Output(time.Now().UTC(), AlertLevel, a.magic(kv...))
+		a.Output(a.now(), AlertLevel, a.magic(kv...))
 	}
 }
 
 // Emerg ...
 func (a *Logger) Emerg(kv ...interface{}) {
 	if a.checkLogLevel(EmergLevel) {
-		a.Output(time.Now().UTC(), EmergLevel, a.magic(kv...))
+		a.Output(a.now(), EmergLevel, a.magic(kv...))
 	}
 }
 
 func (a *Logger) magic(kv ...interface{}) interface{} {
+	return a.magicSkip(a.skip+1, kv)
+}
+
+// magicSkip is magic taking the caller skip frames up.
+func (a *Logger) magicSkip(skip int, kv []interface{}) interface{} {
 	if !a.enableJSON {
-		return fmt.Sprint(kv...)
+		return fmt.Sprint(a.encodeArgs(kv)...)
+	}
+	var v interface{}
+	var m log
+	var keys []string
+	if a.fieldOrder {
+		o := &orderedLog{}
+		o.log = kvLog(log{}, kv, &o.keys)
+		v, m, keys = o, o.log, o.keys
+	} else {
+		m = kvLog(log{}, kv, nil)
+		v = m
 	}
-	m := log{}
 	if a.enableFileLine {
-		m[file] = GetCaller(a.skip)
+		if _, ok := m[file]; ok && a.checkCollisions() {
+			a.collide(m, keys, []string{file})
+		}
+		if _, ok := m[file]; !ok {
+			m[file] = GetCaller(skip)
+		}
 	}
+	return v
+}
+
+// kvLog adds kv to m: key value pairs, the fields of a single map, or
+// the values as message1..N otherwise. The keys added are appended to keys
+// in argument order if it is not nil, the keys of a map sorted.
+func kvLog(m log, kv []interface{}, keys *[]string) log {
 	if len(kv) == 0 {
 		m[message] = nil
+		if keys != nil {
+			*keys = append(*keys, message)
+		}
 		return m
 	}
 	if len(kv) == 1 {
@@ -267,6 +500,9 @@ func (a *Logger) magic(kv ...interface{}) interface{} {
 			for k, v := range val {
 				m[k] = v
 			}
+			if keys != nil {
+				*keys = appendSorted(*keys, val)
+			}
 			return m
 		}
 	}
@@ -280,6 +516,8 @@ func (a *Logger) magic(kv ...interface{}) interface{} {
 				}
 				rVal := kv[i+1]
 				switch rVal.(type) {
+				case LogObjectMarshaler, LogArrayMarshaler:
+					m1[key] = rVal
 				case error:
 					m1[key] = rVal.(error).Error()
 				default:
@@ -290,12 +528,22 @@ func (a *Logger) magic(kv ...interface{}) interface{} {
 		for k, v := range m1 {
 			m[k] = v
 		}
+		if keys != nil {
+			for i := 0; i < len(kv); i += 2 {
+				*keys = append(*keys, kv[i].(string))
+			}
+		}
 		goto jsonBlock
 	}
 kvBlock:
 	for i, val := range kv {
 		key := message + strconv.Itoa(i+1)
+		if keys != nil {
+			*keys = append(*keys, key)
+		}
 		switch val.(type) {
+		case LogObjectMarshaler, LogArrayMarshaler:
+			m[key] = val
 		case error:
 			m[key] = val.(error).Error()
 		default:
@@ -305,13 +553,18 @@ kvBlock:
 jsonBlock:
 	return m
 }
-func (a *Logger) jsonFormat(m log) string {
-	if a.enableGoID {
-		m[goID] = GoroutineID()
+
+// jsonFormat encodes m with its keys sorted or in order if not nil,
+// replacing the values which cannot be encoded, see jsonSafe.
+func (a *Logger) jsonFormat(m log, order []string) string {
+	if order != nil {
+		return string(jsonOrdered(m, order))
 	}
 	res, err := json.Marshal(m)
 	if err != nil {
-		res = []byte(fmt.Sprintf(`{"json-marshal-error":%v}`, err.Error()))
+		if res, err = json.Marshal(jsonSafe(m, 0)); err != nil {
+			res, _ = json.Marshal(map[string]string{"json_error": err.Error()})
+		}
 	}
 	return string(res)
 }
@@ -319,73 +572,74 @@ func (a *Logger) jsonFormat(m log) string {
 // Debugf ...
 func (a *Logger) Debugf(format string, args ...interface{}) {
 	if a.checkLogLevel(DebugLevel) {
-		a.Output(time.Now().UTC(), DebugLevel, a.magic(message, fmt.Sprintf(format, args...)))
+		a.Output(a.now(), DebugLevel, a.magic(a.printfKV(format, args)...))
 	}
 }
 
 // Infof ...
 func (a *Logger) Infof(format string, args ...interface{}) {
 	if a.checkLogLevel(InfoLevel) {
-		a.Output(time.Now().UTC(), InfoLevel, a.magic(message, fmt.Sprintf(format, args...)))
+		a.Output(a.now(), InfoLevel, a.magic(a.printfKV(format, args)...))
 	}
 }
 
 // Noticef ...
 func (a *Logger) Noticef(format string, args ...interface{}) {
 	if a.checkLogLevel(NoticeLevel) {
-		a.Output(time.Now().UTC(), NoticeLevel, a.magic(message, fmt.Sprintf(format, args...)))
+		a.Output(a.now(), NoticeLevel, a.magic(a.printfKV(format, args)...))
 	}
 }
 
 // Warningf ...
 func (a *Logger) Warningf(format string, args ...interface{}) {
 	if a.checkLogLevel(WarningLevel) {
-		a.Output(time.Now().UTC(), WarningLevel, a.magic(message, fmt.Sprintf(format, args...)))
+		a.Output(a.now(), WarningLevel, a.magic(a.printfKV(format, args)...))
 	}
 }
 
 // Errf ...
 func (a *Logger) Errf(format string, args ...interface{}) {
 	if a.checkLogLevel(ErrLevel) {
-		a.Output(time.Now().UTC(), ErrLevel, a.magic(message, fmt.Sprintf(format, args...)))
+		a.Output(a.now(), ErrLevel, a.magic(a.printfKV(format, args)...))
 	}
 }
 
 // Critf ...
 func (a *Logger) Critf(format string, args ...interface{}) {
 	if a.checkLogLevel(CritiLevel) {
-		a.Output(time.Now().UTC(), CritiLevel, a.magic(message, fmt.Sprintf(format, args...)))
+		a.Output(a.now(), CritiLevel, a.magic(a.printfKV(format, args)...))
 	}
 }
 
 // Alertf ...
 func (a *Logger) Alertf(format string, args ...interface{}) {
 	if a.checkLogLevel(AlertLevel) {
-		a.Output(time.Now().UTC(), AlertLevel, a.magic(message, fmt.Sprintf(format, args...)))
+		a.Output(a.now(), AlertLevel, a.magic(a.printfKV(format, args)...))
 	}
 }
 
 // Emergf ...
 func (a *Logger) Emergf(format string, args ...interface{}) {
 	if a.checkLogLevel(EmergLevel) {
-		a.Output(time.Now().UTC(), EmergLevel, a.magic(message, fmt.Sprintf(format, args...)))
+		a.Output(a.now(), EmergLevel, a.magic(a.printfKV(format, args)...))
 	}
 }
 
 // Panicf ...
 func (a *Logger) Panicf(format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	a.Output(time.Now().UTC(), EmergLevel, a.magic(message, msg))
-	panic(msg)
+	kv := a.printfKV(format, args)
+	a.Output(a.now(), EmergLevel, a.magic(kv...))
+	panic(kv[1])
 }
 
 // GetCaller ...
 func GetCaller(layer int) string {
-	_, file, line, ok := runtime.Caller(layer)
-	if !ok {
-		file = "can not find source file"
-		line = 0
-	}
+	_, file, line := caller(layer)
+	return formatCaller(file, line)
+}
+
+// formatCaller renders file, trimmed to its last three path segments, and line.
+func formatCaller(file string, line int) string {
 	files := strings.Split(file, "/")
 	if len(files) > 3 {
 		filesLen := len(files)
@@ -394,6 +648,25 @@ func GetCaller(layer int) string {
 	return fmt.Sprintf("%s:%d", file, line)
 }
 
+// caller reports the program counter, file and line of the frame layer
+// levels above the function calling it.
+func caller(layer int) (pc uintptr, file string, line int) {
+	pc, file, line, ok := runtime.Caller(layer + 1)
+	if !ok {
+		file = "can not find source file"
+		line = 0
+	}
+	return
+}
+
+// callerPC is a cheaper caller returning only the return PC, which is
+// resolved with runtime.CallersFrames when needed.
+func callerPC(layer int) uintptr {
+	var pcs [1]uintptr
+	runtime.Callers(layer+2, pcs[:])
+	return pcs[0]
+}
+
 // Stack formats a stack trace of the calling goroutine
 func Stack() string {
 	buf := make([]byte, 4098)
@@ -405,6 +678,8 @@ func format2Log(i interface{}) log {
 	switch v := i.(type) {
 	case log:
 		return v
+	case *orderedLog:
+		return v.log
 	case map[string]interface{}:
 		return log(v)
 	default:
@@ -412,25 +687,56 @@ func format2Log(i interface{}) log {
 	}
 }
 
+// LevelName returns the name of level, e.g. "WARNING", or "" if unknown.
+func LevelName(level uint32) string {
+	return levels[level]
+}
+
 // ParseLevel takes a string level and returns the logging level constant.
 func ParseLevel(level string) uint32 {
+	if ulevel, ok := levelByName(level); ok {
+		return ulevel
+	}
+	return InfoLevel
+}
+
+// LookupLevel is like ParseLevel but also accepts the level numbers 0-7
+// and returns an error for an unknown level instead of InfoLevel.
+func LookupLevel(level string) (uint32, error) {
+	ulevel, ok := lookupLevel(level)
+	if !ok {
+		return InfoLevel, fmt.Errorf("invalid level %q", level)
+	}
+	return ulevel, nil
+}
+
+// lookupLevel is like ParseLevel but also accepts the level numbers 0-7
+// and reports whether level is valid instead of falling back to InfoLevel.
+func lookupLevel(level string) (uint32, bool) {
+	if n, err := strconv.ParseUint(level, 10, 32); err == nil {
+		return uint32(n), n <= uint64(DebugLevel)
+	}
+	return levelByName(level)
+}
+
+func levelByName(level string) (uint32, bool) {
 	switch strings.ToLower(level) {
 	case "emergency", "emerg":
-		return EmergLevel
+		return EmergLevel, true
 	case "alert":
-		return AlertLevel
+		return AlertLevel, true
 	case "critical", "crit", "criti":
-		return CritiLevel
+		return CritiLevel, true
 	case "error", "err":
-		return ErrLevel
+		return ErrLevel, true
 	case "warning", "warn":
-		return WarningLevel
+		return WarningLevel, true
 	case "notice":
-		return NoticeLevel
+		return NoticeLevel, true
 	case "info":
-		return InfoLevel
+		return InfoLevel, true
 	case "debug":
-		return DebugLevel
+		return DebugLevel, true
 	}
-	return InfoLevel
+	return InfoLevel, false
 }
@@ -0,0 +1,128 @@
+package pkg
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// levelInherit marks a named logger whose level is inherited from its parent.
+const levelInherit = ^uint32(0)
+
+// registry holds every named logger of a tree by its full name.
+type registry struct {
+	mu      sync.Mutex
+	loggers map[string]*Logger
+}
+
+// LoggerLevel describes a logger of a tree and its effective level.
+type LoggerLevel struct {
+	Name      string
+	Level     uint32
+	Inherited bool
+}
+
+// Named returns the child logger called name, creating it on first use.
+// Dots separate levels of the hierarchy, so Named("payments").Named("stripe")
+// and Named("payments.stripe") return the same logger. Its records carry a
+// "logger" field with the full name, and its level is inherited from the
+// nearest ancestor with a level of its own until SetLevel is called on it.
+//
+// A named logger shares the writer of its parent and copies its options,
+// except for Skip: named loggers are meant to be called directly. The
+// options are copied when the logger is created, later changes to its
+// ancestors apply to it for the levels, the fields and SetJSONLog only.
+func (a *Logger) Named(name string) *Logger {
+	l := a
+	for _, part := range strings.Split(name, ".") {
+		if part != "" {
+			l = l.child(part)
+		}
+	}
+	return l
+}
+
+func (a *Logger) child(name string) *Logger {
+	if a.name != "" {
+		name = a.name + "." + name
+	}
+	r := a.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if l, ok := r.loggers[name]; ok {
+		return l
+	}
+	l := &Logger{
+		Out:              a.Out,
+		mu:               a.mu,
+		tf:               a.tf,
+		lf:               a.lf,
+		enableJSON:       a.enableJSON,
+		ulevel:           levelInherit,
+		enableFileLine:   a.enableFileLine,
+		enableGoID:       a.enableGoID,
+		skip:             defaultSkip,
+		format:           a.format,
+		sink:             a.sink,
+		name:             name,
+		parent:           a,
+		root:             a.root,
+		registry:         r,
+		clock:            a.clock,
+		goid:             a.goid,
+		deterministic:    a.deterministic,
+		fieldOrder:       a.fieldOrder,
+		headerKeys:       a.headerKeys,
+		collision:        a.collision,
+		onCollision:      a.onCollision,
+		printfArgs:       a.printfArgs,
+		keys:             a.keys,
+	}
+	r.loggers[name] = l
+	return l
+}
+
+// Name returns the full name of the logger, empty for the root.
+func (a *Logger) Name() string {
+	return a.name
+}
+
+// Lookup returns the logger called name in the tree of a, or nil if it
+// was never created with Named. An empty name returns the root.
+func (a *Logger) Lookup(name string) *Logger {
+	r := a.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.loggers[name]
+}
+
+// Loggers lists every logger of the tree of a sorted by name, the root
+// first, with their effective levels.
+func (a *Logger) Loggers() []LoggerLevel {
+	r := a.registry
+	r.mu.Lock()
+	list := make([]LoggerLevel, 0, len(r.loggers))
+	for name, l := range r.loggers {
+		list = append(list, LoggerLevel{
+			Name:      name,
+			Level:     l.Level(),
+			Inherited: atomic.LoadUint32(&l.ulevel) == levelInherit,
+		})
+	}
+	r.mu.Unlock()
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// ResetLevel makes a named logger inherit its level from its parent again.
+// The root logger is reset to InfoLevel.
+func (a *Logger) ResetLevel() *Logger {
+	if a.parent == nil {
+		return a.SetLevel(InfoLevel)
+	}
+	atomic.StoreUint32(&a.ulevel, levelInherit)
+	return a
+}
@@ -0,0 +1,84 @@
+package pkg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamed(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	root := New(buf, Options{
+		EnableJSON:     true,
+		EnableFileLine: true,
+	})
+	payments := root.Named("payments")
+	stripe := payments.Named("stripe")
+	require.Equal("payments.stripe", stripe.Name())
+	require.Equal(stripe, root.Named("payments.stripe"))
+	require.Equal(stripe, root.Lookup("payments.stripe"))
+	require.Equal(root, root.Lookup(""))
+	require.Nil(root.Lookup("orders"))
+
+	stripe.Info("a", 1)
+	require.Contains(buf.String(), `INFO {"a":1,"file":"logger/pkg/named_test.go:25","logger":"payments.stripe"}`)
+	buf.Reset()
+
+	root.SetLevel(ErrLevel)
+	require.Equal(ErrLevel, stripe.Level())
+	stripe.Info("a", 1)
+	require.Empty(buf.String())
+
+	payments.SetLevel(DebugLevel)
+	require.Equal(DebugLevel, stripe.Level())
+	stripe.Debug("a", 1)
+	require.Contains(buf.String(), "DEBUG")
+	buf.Reset()
+
+	stripe.SetLevel(WarningLevel)
+	stripe.Notice("a", 1)
+	require.Empty(buf.String())
+	require.Equal([]LoggerLevel{
+		{Name: "", Level: ErrLevel},
+		{Name: "payments", Level: DebugLevel},
+		{Name: "payments.stripe", Level: WarningLevel},
+	}, root.Loggers())
+
+	stripe.ResetLevel()
+	payments.ResetLevel()
+	require.Equal(ErrLevel, stripe.Level())
+	root.ResetLevel()
+	require.Equal(InfoLevel, stripe.Level())
+	require.Equal([]LoggerLevel{
+		{Name: "", Level: InfoLevel},
+		{Name: "payments", Level: InfoLevel, Inherited: true},
+		{Name: "payments.stripe", Level: InfoLevel, Inherited: true},
+	}, root.Loggers())
+
+	require.Nil(root.SetVModule("pkg/named_test.go=debug"))
+	stripe.Debug("a", 1)
+	require.Contains(buf.String(), "DEBUG")
+}
+
+func TestNamedSetJSONLog(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	root := New(buf, Options{EnableJSON: true})
+	db := root.Named("db")
+	sql := db.Named("sql")
+	http := root.Named("http")
+
+	db.SetJSONLog()
+	sql.Info("a", 1)
+	require.Regexp(`^\{"a":1,"level":"INFO","logger":"db.sql"`, buf.String())
+	buf.Reset()
+	http.Info("a", 1)
+	require.Regexp(`^\[.+\] INFO \{"a":1,"logger":"http"\}`, buf.String())
+	buf.Reset()
+
+	root.SetJSONLog()
+	http.Info("a", 1)
+	require.Regexp(`^\{"a":1,"level":"INFO","logger":"http"`, buf.String())
+}
@@ -0,0 +1,112 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// defaultHeaderKeys are the keys written first by loggers with FieldOrder.
+var defaultHeaderKeys = []string{timestampKey, levelKey, loggerName, file, goID, message}
+
+// orderedLog is a log with its call-site keys in argument order, built by
+// magic for the loggers with FieldOrder.
+type orderedLog struct {
+	log
+	keys []string
+}
+
+// appendSorted appends the keys of m sorted.
+func appendSorted(keys []string, m map[string]interface{}) []string {
+	start := len(keys)
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys[start:])
+	return keys
+}
+
+// keyOrder returns the keys of m in the order written by loggers with
+// FieldOrder: the header keys, the fields bound to the logger's ancestors
+// and to the logger in the order they were set, the call-site keys, then
+// the remaining keys sorted. It returns nil for the other loggers, whose
+// keys are written sorted.
+func (a *Logger) keyOrder(m log, callKeys []string) []string {
+	if !a.fieldOrder {
+		return nil
+	}
+	order := make([]string, 0, len(m))
+	seen := make(map[string]bool, len(m))
+	add := func(k string) {
+		if _, ok := m[k]; ok && !seen[k] {
+			seen[k] = true
+			order = append(order, k)
+		}
+	}
+	for _, k := range a.headerKeys {
+		add(k)
+	}
+	var chain []*Logger
+	for l := a; l != nil; l = l.parent {
+		chain = append(chain, l)
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		fields, _ := chain[i].fields.Load().([]field)
+		for _, f := range fields {
+			add(f.key)
+		}
+	}
+	for _, k := range callKeys {
+		add(k)
+	}
+	if len(order) < len(m) {
+		rest := make([]string, 0, len(m)-len(order))
+		for k := range m {
+			if !seen[k] {
+				rest = append(rest, k)
+			}
+		}
+		sort.Strings(rest)
+		order = append(order, rest...)
+	}
+	return order
+}
+
+// jsonOrdered encodes m as a JSON object with its keys in order, replacing
+// the values which cannot be encoded as jsonSafe does.
+func jsonOrdered(m log, order []string) []byte {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, k := range order {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if err := writeJSONField(&b, k, m[k]); err != nil {
+			if _, ok := m[k+"_error"]; !ok {
+				b.WriteByte(',')
+				writeJSONField(&b, k+"_error", err.Error())
+			}
+		}
+	}
+	b.WriteByte('}')
+	return b.Bytes()
+}
+
+// writeJSONField writes "k":v and returns the error to annotate v with if
+// it could not be encoded.
+func writeJSONField(b *bytes.Buffer, k string, v interface{}) error {
+	key, _ := json.Marshal(k)
+	b.Write(key)
+	b.WriteByte(':')
+	res, err := json.Marshal(v)
+	var annotation error
+	if err != nil {
+		var safe interface{}
+		safe, annotation = jsonSafeValue(v, 0)
+		if res, err = json.Marshal(safe); err != nil {
+			res, annotation = []byte("null"), err
+		}
+	}
+	b.Write(res)
+	return annotation
+}
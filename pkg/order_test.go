@@ -0,0 +1,70 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldOrder(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	root := New(buf, Options{EnableJSON: true, EnableFileLine: true, FieldOrder: true, Deterministic: true}).SetJSONLog()
+	root.SetFields("service", "api", "env", "dev")
+	db := root.Named("db").SetFields("table", "users", "env", "prod")
+
+	db.Info("zeta", 1, "message", "query", "alpha", 2, "nan", math.NaN(), "ch", make(chan int))
+	require.Regexp(`^\{"timestamp":"2000-01-01T00:00:00Z","level":"INFO","logger":"db","file":"logger/pkg/order_test.go:\d+","message":"query","service":"api","env":"prod","table":"users","zeta":1,"alpha":2,"nan":"NaN","ch":"chan int","ch_error":"json: unsupported type: chan int"\}\n$`, buf.String())
+	buf.Reset()
+
+	root.Info("b", 1, "a", 2)
+	root.Info(map[string]interface{}{"y": 1, "x": 2})
+	root.Info("m1", "m2", 3)
+	require.Contains(buf.String(), `"level":"INFO","file":`)
+	require.Contains(buf.String(), `"service":"api","env":"dev","b":1,"a":2}`)
+	require.Contains(buf.String(), `"service":"api","env":"dev","x":2,"y":1}`)
+	require.Contains(buf.String(), `"service":"api","env":"dev","message1":"m1","message2":"m2","message3":3}`)
+	buf.Reset()
+
+	text := New(buf, Options{EnableJSON: true, FieldOrder: true, HeaderKeys: []string{"message"}, Deterministic: true})
+	text.Info("b", 1, "message", "hi", "a", 2)
+	require.Equal("[2000-01-01T00:00:00Z] INFO {\"message\":\"hi\",\"b\":1,\"a\":2}\n", buf.String())
+	buf.Reset()
+
+	text.Output(text.now(), InfoLevel, map[string]interface{}{"z": 1, "message": "x", "a": 2})
+	require.Equal("[2000-01-01T00:00:00Z] INFO {\"message\":\"x\",\"a\":2,\"z\":1}\n", buf.String())
+	buf.Reset()
+
+	logfmt := New(buf, Options{EnableJSON: true, FieldOrder: true, Format: "logfmt", Deterministic: true})
+	logfmt.Named("db").Info("b", 1, "message", "hi", "a", 2)
+	require.Equal("timestamp=2000-01-01T00:00:00Z level=INFO logger=db message=hi b=1 a=2\n", buf.String())
+	buf.Reset()
+
+	console := New(buf, Options{EnableJSON: true, FieldOrder: true, Format: "console", Deterministic: true})
+	console.Info("b", 1, "message", "hi", "a", 2)
+	require.Equal("2000-01-01T00:00:00Z INFO    hi b=1 a=2\n", buf.String())
+
+	var keys []string
+	h := slogFunc(func(r slog.Record) {
+		r.Attrs(func(a slog.Attr) bool {
+			keys = append(keys, a.Key)
+			return true
+		})
+	})
+	NewSlogLogger(h, Options{EnableJSON: true, FieldOrder: true}).Info("b", 1, "message", "hi", "a", 2)
+	require.Equal([]string{"b", "a"}, keys)
+}
+
+type slogFunc func(slog.Record)
+
+func (f slogFunc) Enabled(context.Context, slog.Level) bool { return true }
+func (f slogFunc) Handle(_ context.Context, r slog.Record) error {
+	f(r)
+	return nil
+}
+func (f slogFunc) WithAttrs([]slog.Attr) slog.Handler { return f }
+func (f slogFunc) WithGroup(string) slog.Handler      { return f }
@@ -0,0 +1,29 @@
+package pkg
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// formatKey is the key of the format string recorded by loggers with
+// PrintfArgs.
+const formatKey = "format"
+
+// printfKV returns the key value pairs of a record of the *f methods: the
+// message, and for loggers with PrintfArgs the format string and the args
+// as arg0..argN, errors as objects of their message and type.
+func (a *Logger) printfKV(format string, args []interface{}) []interface{} {
+	msg := fmt.Sprintf(format, args...)
+	if !a.enableJSON || !a.printfArgs {
+		return []interface{}{message, msg}
+	}
+	kv := make([]interface{}, 0, 2*len(args)+4)
+	kv = append(kv, message, msg, formatKey, format)
+	for i, v := range args {
+		if err, ok := v.(error); ok {
+			v = map[string]interface{}{"error": err.Error(), "type": fmt.Sprintf("%T", err)}
+		}
+		kv = append(kv, "arg"+strconv.Itoa(i), v)
+	}
+	return kv
+}
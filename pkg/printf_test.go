@@ -0,0 +1,42 @@
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintfArgs(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+
+	logger := New(buf, Options{EnableJSON: true, Deterministic: true}).SetJSONLog()
+	logger.Infof("user %s paid %d", "u1", 10)
+	require.Equal(`{"level":"INFO","message":"user u1 paid 10","timestamp":"2000-01-01T00:00:00Z"}`+"\n", buf.String())
+	buf.Reset()
+
+	logger = New(buf, Options{EnableJSON: true, Deterministic: true, PrintfArgs: true}).SetJSONLog()
+	logger.Infof("user %s paid %d", "u1", 10)
+	require.Equal(`{"arg0":"u1","arg1":10,"format":"user %s paid %d","level":"INFO","message":"user u1 paid 10","timestamp":"2000-01-01T00:00:00Z"}`+"\n", buf.String())
+	buf.Reset()
+
+	logger.Named("db").Errf("query failed: %v", errors.New("timeout"))
+	require.Equal(`{"arg0":{"error":"timeout","type":"*errors.errorString"},"format":"query failed: %v","level":"ERR","logger":"db","message":"query failed: timeout","timestamp":"2000-01-01T00:00:00Z"}`+"\n", buf.String())
+	buf.Reset()
+
+	logger.Every(0).Warningf("disk %s", "sda")
+	require.Contains(buf.String(), `"arg0":"sda","format":"disk %s"`)
+	buf.Reset()
+
+	require.PanicsWithValue("boom 1", func() {
+		logger.Panicf("boom %d", 1)
+	})
+	require.Contains(buf.String(), `"arg0":1,"format":"boom %d","level":"EMERG","message":"boom 1"`)
+	buf.Reset()
+
+	New(buf, Options{Deterministic: true, PrintfArgs: true}).Infof("user %s", "u1")
+	require.Contains(buf.String(), "user u1")
+	require.NotContains(buf.String(), "arg0")
+}
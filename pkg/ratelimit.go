@@ -0,0 +1,192 @@
+package pkg
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	limitEvery uint8 = iota
+	limitFirstN
+	limitEveryN
+)
+
+// siteKey identifies a rate limited call site.
+type siteKey struct {
+	pc    uintptr
+	kind  uint8
+	param int64
+}
+
+// site holds the rate limiting state of a single call site.
+type site struct {
+	calls uint64
+	last  int64
+}
+
+// Limited is a logger which only writes records when the call site
+// passes its limit. Call sites are keyed by the caller PC, so every
+// statement is throttled independently.
+type Limited struct {
+	logger *Logger
+	kind   uint8
+	param  int64
+}
+
+// Every returns a Limited logger writing at most one record per call site
+// in every interval d.
+func (a *Logger) Every(d time.Duration) *Limited {
+	return &Limited{logger: a, kind: limitEvery, param: int64(d)}
+}
+
+// FirstN returns a Limited logger writing only the first n records of
+// every call site, none if n is not positive.
+func (a *Logger) FirstN(n int) *Limited {
+	if n < 0 {
+		n = 0
+	}
+	return &Limited{logger: a, kind: limitFirstN, param: int64(n)}
+}
+
+// EveryN returns a Limited logger writing the 1st, (n+1)th, (2n+1)th...
+// record of every call site.
+func (a *Logger) EveryN(n int) *Limited {
+	return &Limited{logger: a, kind: limitEveryN, param: int64(n)}
+}
+
+// allow must be called directly by the logging methods of Limited.
+func (l *Limited) allow() bool {
+	pc, _, _ := caller(l.logger.skip - 1)
+	key := siteKey{pc: pc, kind: l.kind, param: l.param}
+	v, ok := l.logger.sites.Load(key)
+	if !ok {
+		v, _ = l.logger.sites.LoadOrStore(key, &site{})
+	}
+	s := v.(*site)
+	switch l.kind {
+	case limitFirstN:
+		return atomic.AddUint64(&s.calls, 1) <= uint64(l.param)
+	case limitEveryN:
+		n := atomic.AddUint64(&s.calls, 1) - 1
+		return l.param <= 1 || n%uint64(l.param) == 0
+	default:
+		now := l.logger.clock().UnixNano()
+		last := atomic.LoadInt64(&s.last)
+		if last != 0 && now-last < l.param {
+			return false
+		}
+		return atomic.CompareAndSwapInt64(&s.last, last, now)
+	}
+}
+
+// Debug ...
+func (l *Limited) Debug(kv ...interface{}) {
+	if l.logger.checkLogLevel(DebugLevel) && l.allow() {
+		l.logger.Output(l.logger.now(), DebugLevel, l.logger.magic(kv...))
+	}
+}
+
+// Info ...
+func (l *Limited) Info(kv ...interface{}) {
+	if l.logger.checkLogLevel(InfoLevel) && l.allow() {
+		l.logger.Output(l.logger.now(), InfoLevel, l.logger.magic(kv...))
+	}
+}
+
+// Notice ...
+func (l *Limited) Notice(kv ...interface{}) {
+	if l.logger.checkLogLevel(NoticeLevel) && l.allow() {
+		l.logger.Output(l.logger.now(), NoticeLevel, l.logger.magic(kv...))
+	}
+}
+
+// Warning ...
+func (l *Limited) Warning(kv ...interface{}) {
+	if l.logger.checkLogLevel(WarningLevel) && l.allow() {
+		l.logger.Output(l.logger.now(), WarningLevel, l.logger.magic(kv...))
+	}
+}
+
+// Err ...
+func (l *Limited) Err(kv ...interface{}) {
+	if l.logger.checkLogLevel(ErrLevel) && l.allow() {
+		l.logger.Output(l.logger.now(), ErrLevel, l.logger.magic(kv...))
+	}
+}
+
+// Crit ...
+func (l *Limited) Crit(kv ...interface{}) {
+	if l.logger.checkLogLevel(CritiLevel) && l.allow() {
+		l.logger.Output(l.logger.now(), CritiLevel, l.logger.magic(kv...))
+	}
+}
+
+// Alert ...
+func (l *Limited) Alert(kv ...interface{}) {
+	if l.logger.checkLogLevel(AlertLevel) && l.allow() {
+		l.logger.Output(l.logger.now(), AlertLevel, l.logger.magic(kv...))
+	}
+}
+
+// Emerg ...
+func (l *Limited) Emerg(kv ...interface{}) {
+	if l.logger.checkLogLevel(EmergLevel) && l.allow() {
+		l.logger.Output(l.logger.now(), EmergLevel, l.logger.magic(kv...))
+	}
+}
+
+// Debugf ...
+func (l *Limited) Debugf(format string, args ...interface{}) {
+	if l.logger.checkLogLevel(DebugLevel) && l.allow() {
+		l.logger.Output(l.logger.now(), DebugLevel, l.logger.magic(l.logger.printfKV(format, args)...))
+	}
+}
+
+// Infof ...
+func (l *Limited) Infof(format string, args ...interface{}) {
+	if l.logger.checkLogLevel(InfoLevel) && l.allow() {
+		l.logger.Output(l.logger.now(), InfoLevel, l.logger.magic(l.logger.printfKV(format, args)...))
+	}
+}
+
+// Noticef ...
+func (l *Limited) Noticef(format string, args ...interface{}) {
+	if l.logger.checkLogLevel(NoticeLevel) && l.allow() {
+		l.logger.Output(l.logger.now(), NoticeLevel, l.logger.magic(l.logger.printfKV(format, args)...))
+	}
+}
+
+// Warningf ...
+func (l *Limited) Warningf(format string, args ...interface{}) {
+	if l.logger.checkLogLevel(WarningLevel) && l.allow() {
+		l.logger.Output(l.logger.now(), WarningLevel, l.logger.magic(l.logger.printfKV(format, args)...))
+	}
+}
+
+// Errf ...
+func (l *Limited) Errf(format string, args ...interface{}) {
+	if l.logger.checkLogLevel(ErrLevel) && l.allow() {
+		l.logger.Output(l.logger.now(), ErrLevel, l.logger.magic(l.logger.printfKV(format, args)...))
+	}
+}
+
+// Critf ...
+func (l *Limited) Critf(format string, args ...interface{}) {
+	if l.logger.checkLogLevel(CritiLevel) && l.allow() {
+		l.logger.Output(l.logger.now(), CritiLevel, l.logger.magic(l.logger.printfKV(format, args)...))
+	}
+}
+
+// Alertf ...
+func (l *Limited) Alertf(format string, args ...interface{}) {
+	if l.logger.checkLogLevel(AlertLevel) && l.allow() {
+		l.logger.Output(l.logger.now(), AlertLevel, l.logger.magic(l.logger.printfKV(format, args)...))
+	}
+}
+
+// Emergf ...
+func (l *Limited) Emergf(format string, args ...interface{}) {
+	if l.logger.checkLogLevel(EmergLevel) && l.allow() {
+		l.logger.Output(l.logger.now(), EmergLevel, l.logger.magic(l.logger.printfKV(format, args)...))
+	}
+}
@@ -0,0 +1,65 @@
+package pkg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimited(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{
+		EnableJSON:     true,
+		EnableFileLine: true,
+	})
+
+	for i := 0; i < 10; i++ {
+		logger.FirstN(3).Info("i", i)
+	}
+	require.Equal(3, strings.Count(buf.String(), "\n"))
+	require.Contains(buf.String(), `"file":"logger/pkg/ratelimit_test.go:21"`)
+	require.Contains(buf.String(), `"i":2`)
+	require.NotContains(buf.String(), `"i":3`)
+	buf.Reset()
+
+	for i := 0; i < 3; i++ {
+		logger.FirstN(-1).Info("i", i)
+		logger.FirstN(0).Info("i", i)
+	}
+	require.Empty(buf.String())
+
+	for i := 0; i < 10; i++ {
+		logger.EveryN(4).Warningf("i=%d", i)
+	}
+	require.Equal(3, strings.Count(buf.String(), "\n"))
+	require.Contains(buf.String(), `"message":"i=0"`)
+	require.Contains(buf.String(), `"message":"i=4"`)
+	require.Contains(buf.String(), `"message":"i=8"`)
+	buf.Reset()
+
+	for i := 0; i < 10; i++ {
+		logger.Every(time.Hour).Info("i", i)
+		logger.Every(time.Hour).Info("j", i)
+	}
+	require.Equal(2, strings.Count(buf.String(), "\n"))
+	buf.Reset()
+
+	for i := 0; i < 3; i++ {
+		logger.Every(time.Nanosecond).Info("i", i)
+		time.Sleep(time.Millisecond)
+	}
+	require.Equal(3, strings.Count(buf.String(), "\n"))
+	buf.Reset()
+
+	for i := 0; i < 10; i++ {
+		logger.FirstN(3).Debug("i", i)
+	}
+	require.Empty(buf.String())
+	logger.SetLevel(DebugLevel)
+	logger.FirstN(3).Debug("i", 0)
+	require.Contains(buf.String(), "DEBUG")
+}
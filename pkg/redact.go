@@ -0,0 +1,242 @@
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// defaultMask replaces redacted values if Redaction.Mask is empty.
+const defaultMask = "[REDACTED]"
+
+// Redaction describes the values masked before records are written.
+type Redaction struct {
+	// Keys are the names of the fields to mask, or path.Match patterns
+	// such as "*_token", compared case-insensitively at any depth.
+	Keys []string
+	// Values are regular expressions whose matches in string values,
+	// messages included, are masked.
+	Values []string
+	// Hash replaces values with "sha256:" and the start of the hex SHA-256
+	// of Salt and the value, so equal values can still be correlated.
+	Hash bool
+	// Salt is prepended to the values hashed.
+	Salt string
+	// Mask replaces the values if Hash is false, "[REDACTED]" if empty.
+	Mask string
+	// Cards masks the card numbers, 13 to 19 digits optionally separated
+	// by spaces or dashes which pass the Luhn check, in string values.
+	Cards bool
+}
+
+// cardNumber matches the candidates for Redaction.Cards.
+var cardNumber = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+
+// DefaultRedaction masks common credentials, card numbers and JWTs.
+var DefaultRedaction = Redaction{
+	Keys: []string{"password", "passwd", "secret", "*_secret", "token", "*_token",
+		"authorization", "cookie", "set-cookie", "api_key", "apikey"},
+	Values: []string{
+		`\beyJ[\w-]*\.eyJ[\w-]*\.[\w-]+`,
+	},
+	Cards: true,
+}
+
+type redactor struct {
+	keys   []string
+	values []*regexp.Regexp
+	hash   bool
+	salt   string
+	mask   string
+	cards  bool
+}
+
+// SetRedaction masks or hashes the values described by r in the records of
+// the logger's tree, before they are formatted. A nil r disables it.
+func (a *Logger) SetRedaction(r *Redaction) error {
+	if r == nil {
+		a.root.redactor.Store((*redactor)(nil))
+		return nil
+	}
+	rd := &redactor{hash: r.Hash, salt: r.Salt, mask: r.Mask, cards: r.Cards}
+	if rd.mask == "" {
+		rd.mask = defaultMask
+	}
+	for _, k := range r.Keys {
+		k = strings.ToLower(k)
+		if _, err := path.Match(k, ""); err != nil {
+			return fmt.Errorf("redaction: invalid key pattern %q", k)
+		}
+		rd.keys = append(rd.keys, k)
+	}
+	for _, v := range r.Values {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			return fmt.Errorf("redaction: %v", err)
+		}
+		rd.values = append(rd.values, re)
+	}
+	a.root.redactor.Store(rd)
+	return nil
+}
+
+func (a *Logger) redact(m log) {
+	if r, _ := a.root.redactor.Load().(*redactor); r != nil {
+		for k, v := range m {
+			m[k] = r.field(k, v, 0)
+		}
+	}
+}
+
+func (r *redactor) matchKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range r.keys {
+		if ok, _ := path.Match(k, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *redactor) field(key string, v interface{}, depth int) interface{} {
+	if r.matchKey(key) {
+		if !r.hash {
+			return r.mask
+		}
+		return r.replace(hashInput(v))
+	}
+	return r.value(v, depth)
+}
+
+// value returns v with its sensitive parts masked, maps and slices are
+// copied instead of modified.
+func (r *redactor) value(v interface{}, depth int) interface{} {
+	if depth >= maxJSONDepth {
+		return v
+	}
+	switch val := v.(type) {
+	case string:
+		for _, re := range r.values {
+			val = re.ReplaceAllStringFunc(val, r.replace)
+		}
+		if r.cards {
+			val = cardNumber.ReplaceAllStringFunc(val, func(s string) string {
+				if !luhn(s) {
+					return s
+				}
+				return r.replace(s)
+			})
+		}
+		return val
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, v := range val {
+			m[k] = r.field(k, v, depth+1)
+		}
+		return m
+	case log:
+		m := make(log, len(val))
+		for k, v := range val {
+			m[k] = r.field(k, v, depth+1)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(val))
+		for i, v := range val {
+			s[i] = r.value(v, depth+1)
+		}
+		return s
+	case []string:
+		s := make([]string, len(val))
+		for i, v := range val {
+			s[i] = r.value(v, depth+1).(string)
+		}
+		return s
+	case []byte:
+		return v
+	}
+	return r.reflectValue(v, depth)
+}
+
+// reflectValue is value for the other maps with string keys, such as
+// http.Header, and slices, copied to a map[string]interface{} and an
+// []interface{}.
+func (r *redactor) reflectValue(v interface{}, depth int) interface{} {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.IsNil() || rv.Type().Key().Kind() != reflect.String {
+			return v
+		}
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			m[k] = r.field(k, iter.Value().Interface(), depth+1)
+		}
+		return m
+	case reflect.Slice:
+		if rv.IsNil() {
+			return v
+		}
+		s := make([]interface{}, rv.Len())
+		for i := range s {
+			s[i] = r.value(rv.Index(i).Interface(), depth+1)
+		}
+		return s
+	}
+	return v
+}
+
+func (r *redactor) replace(s string) string {
+	if r.hash {
+		return hashValue(r.salt, s)
+	}
+	return r.mask
+}
+
+// luhn reports whether the digits of s pass the Luhn check, other
+// characters are skipped.
+func luhn(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// hashInput returns the text of v which is hashed: strings as they are,
+// other values as JSON, or their type if encoding/json fails, e.g. for
+// cyclic values.
+func hashInput(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	if b, err := json.Marshal(v); err == nil {
+		return string(b)
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// hashValue returns "sha256:" and the first 16 hex digits of the SHA-256
+// of salt and s.
+func hashValue(salt, s string) string {
+	sum := sha256.Sum256([]byte(salt + s))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
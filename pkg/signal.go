@@ -0,0 +1,63 @@
+//go:build !windows && !plan9 && !js && !wasip1
+// +build !windows,!plan9,!js,!wasip1
+
+package pkg
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// NotifySignals installs a signal handler changing the level of a: SIGUSR1
+// makes it one level more verbose, up to DebugLevel, and SIGUSR2 resets it
+// to the level a had when NotifySignals was called. Every change is written
+// as a NOTICE record whatever the level. The returned function removes the
+// handler.
+func (a *Logger) NotifySignals() (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR1, syscall.SIGUSR2)
+	stopHandler := a.handleSignals(ch)
+	return func() {
+		signal.Stop(ch)
+		stopHandler()
+	}
+}
+
+func (a *Logger) handleSignals(ch <-chan os.Signal) (stop func()) {
+	configured := a.Level()
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-ch:
+				from, to := a.Level(), configured
+				if sig == syscall.SIGUSR1 {
+					to = from
+					if to < DebugLevel {
+						to++
+					}
+				}
+				a.SetLevel(to)
+				a.Output(a.now(), NoticeLevel, log{
+					message:  "log level changed by signal",
+					"signal": sig.String(),
+					"from":   levels[from],
+					"to":     levels[to],
+				})
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-exited
+		})
+	}
+}
@@ -0,0 +1,9 @@
+//go:build windows || plan9 || js || wasip1
+// +build windows plan9 js wasip1
+
+package pkg
+
+// NotifySignals is a no-op on platforms without SIGUSR1 and SIGUSR2.
+func (a *Logger) NotifySignals() (stop func()) {
+	return func() {}
+}
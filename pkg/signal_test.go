@@ -0,0 +1,63 @@
+//go:build !windows && !plan9 && !js && !wasip1
+// +build !windows,!plan9,!js,!wasip1
+
+package pkg
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSignals(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{EnableJSON: true})
+	logger.SetLevel(ErrLevel)
+
+	ch := make(chan os.Signal)
+	stop := logger.handleSignals(ch)
+	defer stop()
+
+	ch <- syscall.SIGUSR1
+	ch <- syscall.SIGUSR1
+	waitLevel(logger, NoticeLevel)
+	require.Equal(NoticeLevel, logger.Level())
+	for i := 0; i < 5; i++ {
+		ch <- syscall.SIGUSR1
+	}
+	waitLevel(logger, DebugLevel)
+	require.Equal(DebugLevel, logger.Level())
+	ch <- syscall.SIGUSR2
+	waitLevel(logger, ErrLevel)
+	stop()
+	stop()
+	require.Equal(ErrLevel, logger.Level())
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(lines, 8)
+	require.Contains(lines[0], `NOTICE {"from":"ERR","message":"log level changed by signal","signal":"user defined signal 1","to":"WARNING"}`)
+	require.Contains(lines[7], `"from":"DEBUG","message":"log level changed by signal","signal":"user defined signal 2","to":"ERR"`)
+}
+
+func TestNotifySignals(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{EnableJSON: true})
+	stop := logger.NotifySignals()
+	defer stop()
+
+	require.Nil(syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+	waitLevel(logger, DebugLevel)
+	require.Equal(DebugLevel, logger.Level())
+}
+
+func waitLevel(logger *Logger, level uint32) {
+	for i := 0; i < 100 && logger.Level() != level; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
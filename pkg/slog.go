@@ -0,0 +1,268 @@
+package pkg
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// SlogLevel returns the slog level of a level: DebugLevel, InfoLevel,
+// WarningLevel and ErrLevel map to the slog levels of the same name, the
+// other levels sit between or above them.
+func SlogLevel(level uint32) slog.Level {
+	switch level {
+	case EmergLevel:
+		return slog.LevelError + 12
+	case AlertLevel:
+		return slog.LevelError + 8
+	case CritiLevel:
+		return slog.LevelError + 4
+	case ErrLevel:
+		return slog.LevelError
+	case WarningLevel:
+		return slog.LevelWarn
+	case NoticeLevel:
+		return slog.LevelInfo + 2
+	case InfoLevel:
+		return slog.LevelInfo
+	}
+	return slog.LevelDebug
+}
+
+// LevelFromSlog is the inverse of SlogLevel, slog levels between two
+// levels map to the less severe one.
+func LevelFromSlog(level slog.Level) uint32 {
+	switch {
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelInfo+2:
+		return InfoLevel
+	case level < slog.LevelWarn:
+		return NoticeLevel
+	case level < slog.LevelError:
+		return WarningLevel
+	case level < slog.LevelError+4:
+		return ErrLevel
+	case level < slog.LevelError+8:
+		return CritiLevel
+	case level < slog.LevelError+12:
+		return AlertLevel
+	}
+	return EmergLevel
+}
+
+// SlogHandler is a slog.Handler writing records through a Logger, so slog
+// and the Logger methods produce the same lines. Attrs become fields,
+// groups nested objects, and the record message the "message" field.
+type SlogHandler struct {
+	logger *Logger
+	fields map[string]interface{}
+	groups []string
+}
+
+// NewSlogHandler returns a slog.Handler writing to l.
+func NewSlogHandler(l *Logger) *SlogHandler {
+	return &SlogHandler{logger: l, fields: map[string]interface{}{}}
+}
+
+// Enabled ...
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.checkLevelPC(LevelFromSlog(level), 0)
+}
+
+// Handle ...
+func (h *SlogHandler) Handle(_ context.Context, r slog.Record) error {
+	level := LevelFromSlog(r.Level)
+	if !h.logger.checkLevelPC(level, r.PC) {
+		return nil
+	}
+	m := log(h.copyFields())
+	if h.logger.enableFileLine && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		m[file] = formatCaller(frame.File, frame.Line)
+	}
+	group := h.group(m)
+	r.Attrs(func(attr slog.Attr) bool {
+		addAttr(group, attr)
+		return true
+	})
+	h.pruneGroups(m)
+	m[message] = r.Message
+	t := r.Time
+	if t.IsZero() || h.logger.deterministic {
+		t = h.logger.clock()
+	}
+	return h.logger.Output(t.UTC(), level, m)
+}
+
+// WithAttrs ...
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := &SlogHandler{logger: h.logger, fields: h.copyFields(), groups: h.groups}
+	group := h2.group(h2.fields)
+	for _, attr := range attrs {
+		addAttr(group, attr)
+	}
+	return h2
+}
+
+// WithGroup ...
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	groups := make([]string, len(h.groups), len(h.groups)+1)
+	copy(groups, h.groups)
+	return &SlogHandler{logger: h.logger, fields: h.fields, groups: append(groups, name)}
+}
+
+// copyFields copies the fields of h, deeply along the open groups which
+// are the only maps modified afterwards.
+func (h *SlogHandler) copyFields() map[string]interface{} {
+	m := make(map[string]interface{}, len(h.fields)+4)
+	for k, v := range h.fields {
+		m[k] = v
+	}
+	parent := m
+	for _, g := range h.groups {
+		sub := map[string]interface{}{}
+		if prev, ok := parent[g].(map[string]interface{}); ok {
+			for k, v := range prev {
+				sub[k] = v
+			}
+		}
+		parent[g] = sub
+		parent = sub
+	}
+	return m
+}
+
+// group returns the map of the innermost open group of m, which must
+// have been returned by copyFields.
+func (h *SlogHandler) group(m map[string]interface{}) map[string]interface{} {
+	for _, g := range h.groups {
+		m = m[g].(map[string]interface{})
+	}
+	return m
+}
+
+// pruneGroups removes the innermost open groups of m left empty.
+func (h *SlogHandler) pruneGroups(m map[string]interface{}) {
+	for i := len(h.groups); i > 0; i-- {
+		parent := m
+		for _, g := range h.groups[:i-1] {
+			parent = parent[g].(map[string]interface{})
+		}
+		if len(parent[h.groups[i-1]].(map[string]interface{})) > 0 {
+			return
+		}
+		delete(parent, h.groups[i-1])
+	}
+}
+
+func addAttr(m map[string]interface{}, attr slog.Attr) {
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	v := attr.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		attrs := v.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		sub := m
+		if attr.Key != "" {
+			sub = map[string]interface{}{}
+			m[attr.Key] = sub
+		}
+		for _, a := range attrs {
+			addAttr(sub, a)
+		}
+		return
+	}
+	switch val := v.Any().(type) {
+	case error:
+		m[attr.Key] = val.Error()
+	default:
+		m[attr.Key] = val
+	}
+}
+
+// NewSlogLogger returns a Logger delivering its records to h instead of
+// writing them, for code using the Logger API in applications built on
+// slog. The "message" field becomes the record message, the other fields,
+// message1..N included, become attrs sorted by key and nested maps groups.
+// The level defaults to DebugLevel, leaving the filtering to h, and
+// EnableJSON to true; the output format options are ignored.
+func NewSlogLogger(h slog.Handler, options ...Options) *Logger {
+	opt := Options{EnableJSON: true}
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if opt.Level == "" {
+		opt.Level = "debug"
+	}
+	logger := New(nil, opt)
+	logger.sink = slogSink{h}
+	return logger
+}
+
+// slogSink delivers the records of a Logger to a slog.Handler.
+type slogSink struct {
+	handler slog.Handler
+}
+
+func (s slogSink) output(t time.Time, level uint32, pc uintptr, m log, order []string) error {
+	ctx := context.Background()
+	slevel := SlogLevel(level)
+	if !s.handler.Enabled(ctx, slevel) {
+		return nil
+	}
+	var msg string
+	if v, ok := m[message]; ok {
+		if v != nil {
+			msg = textValue(v)
+		}
+		delete(m, message)
+	}
+	r := slog.NewRecord(t, slevel, msg, pc)
+	if order == nil {
+		r.AddAttrs(slogAttrs(m)...)
+		return s.handler.Handle(ctx, r)
+	}
+	for _, k := range order {
+		if v, ok := m[k]; ok {
+			r.AddAttrs(slogAttr(k, v))
+		}
+	}
+	return s.handler.Handle(ctx, r)
+}
+
+// slogAttrs converts m to attrs sorted by key, nested maps to groups.
+func slogAttrs(m map[string]interface{}) []slog.Attr {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slogAttr(k, m[k]))
+	}
+	return attrs
+}
+
+// slogAttr converts a field to an attr, nested maps to groups.
+func slogAttr(k string, v interface{}) slog.Attr {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return slog.Attr{Key: k, Value: slog.GroupValue(slogAttrs(val)...)}
+	case log:
+		return slog.Attr{Key: k, Value: slog.GroupValue(slogAttrs(val)...)}
+	}
+	return slog.Any(k, v)
+}
@@ -0,0 +1,134 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type token string
+
+func (token) LogValue() slog.Value {
+	return slog.StringValue("REDACTED")
+}
+
+func TestSlogLevel(t *testing.T) {
+	require := require.New(t)
+	for level := EmergLevel; level <= DebugLevel; level++ {
+		require.Equal(level, LevelFromSlog(SlogLevel(level)))
+	}
+	require.Equal(slog.LevelWarn, SlogLevel(WarningLevel))
+	require.Equal(DebugLevel, LevelFromSlog(slog.LevelDebug-4))
+	require.Equal(InfoLevel, LevelFromSlog(slog.LevelInfo+1))
+	require.Equal(ErrLevel, LevelFromSlog(slog.LevelError+1))
+	require.Equal(EmergLevel, LevelFromSlog(slog.LevelError+100))
+}
+
+func TestSlogHandler(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{
+		EnableJSON:     true,
+		EnableFileLine: true,
+	})
+	sl := slog.New(NewSlogHandler(logger))
+
+	sl.Info("hello", "a", 1, "err", errors.New("x"), "token", token("secret"))
+	logger.Info("message", "hello", "a", 1, "err", errors.New("x"), "token", "REDACTED")
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(lines, 2)
+	require.Contains(string(lines[0]), `INFO {"a":1,"err":"x","file":"logger/pkg/slog_test.go:42","message":"hello","token":"REDACTED"}`)
+	require.Contains(string(lines[1]), `INFO {"a":1,"err":"x","file":"logger/pkg/slog_test.go:43","message":"hello","token":"REDACTED"}`)
+	buf.Reset()
+
+	sl.Debug("hidden")
+	require.False(sl.Enabled(context.Background(), slog.LevelDebug))
+	require.Empty(buf.String())
+	logger.SetLevel(DebugLevel)
+	sl.Log(context.Background(), slog.LevelInfo+2, "notice")
+	require.Contains(buf.String(), `NOTICE {"file":"logger/pkg/slog_test.go:54","message":"notice"}`)
+	buf.Reset()
+
+	logger.SetJSONLog()
+	sl.With("a", 1).WithGroup("req").With("id", 7).WithGroup("empty").Warn("w",
+		slog.Group("user", "name", "bob"), slog.Group("none"), slog.Attr{}, slog.Group("", "inline", true))
+	v := map[string]interface{}{}
+	require.Nil(json.Unmarshal(buf.Bytes(), &v))
+	require.Equal(float64(1), v["a"])
+	require.Equal(map[string]interface{}{
+		"id":    float64(7),
+		"empty": map[string]interface{}{"user": map[string]interface{}{"name": "bob"}, "inline": true},
+	}, v["req"])
+	require.Equal("WARNING", v["level"])
+	require.Equal("w", v["message"])
+	buf.Reset()
+
+	h := NewSlogHandler(logger).WithGroup("g")
+	require.Equal(h, h.WithGroup("").WithAttrs(nil))
+	slog.New(h).WithGroup("h").Info("no attrs")
+	v = map[string]interface{}{}
+	require.Nil(json.Unmarshal(buf.Bytes(), &v))
+	require.Nil(v["g"])
+	buf.Reset()
+
+	r := slog.NewRecord(time.Time{}, slog.LevelError, "no pc", 0)
+	require.Nil(h.Handle(context.Background(), r))
+	require.Contains(buf.String(), `"level":"ERR","message":"no pc","timestamp":"`)
+	buf.Reset()
+
+	require.Nil(logger.SetVModule("pkg/slog_test.go=err"))
+	require.True(sl.Enabled(context.Background(), slog.LevelInfo))
+	sl.Info("filtered")
+	require.Empty(buf.String())
+	sl.Error("kept")
+	require.Contains(buf.String(), `"message":"kept"`)
+}
+
+func TestSlogLogger(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	h := slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := NewSlogLogger(h)
+	require.Equal(DebugLevel, logger.Level())
+
+	logger.Info("key", "val", "err", errors.New("x"), "m", map[string]interface{}{"b": 2, "a": 1})
+	require.Regexp(`^time=\S+ level=INFO msg="" err=x key=val m.a=1 m.b=2\n$`, buf.String())
+	buf.Reset()
+
+	logger.Debug("hidden")
+	require.Empty(buf.String())
+	logger.Warningf("hello %s", "world")
+	require.Regexp(`^time=\S+ level=WARN msg="hello world"\n$`, buf.String())
+	buf.Reset()
+
+	logger.Named("db").SetFields("service", "api").Notice("slow", "query", 1)
+	require.Regexp(`^time=\S+ level=INFO\+2 msg="" logger=db message1=slow message2=query message3=1 service=api\n$`, buf.String())
+	buf.Reset()
+
+	require.True(logger.NotNil(errors.New("boom"), "id", 7))
+	require.Regexp(`^time=\S+ level=ERROR msg="" error=boom id=7\n$`, buf.String())
+	buf.Reset()
+
+	logger = NewSlogLogger(h, Options{EnableFileLine: true, EnableJSON: true, Level: "err"})
+	logger.Info("hidden")
+	logger.Emerg()
+	require.Regexp(`^time=\S+ level=ERROR\+12 msg="" file=logger/pkg/slog_test.go:120\n$`, buf.String())
+	buf.Reset()
+
+	h = slog.NewTextHandler(buf, &slog.HandlerOptions{AddSource: true})
+	logger = NewSlogLogger(h)
+	logger.Info("a", 1)
+	require.Regexp(`source=\S+/pkg/slog_test.go:126 `, buf.String())
+	buf.Reset()
+	warn := func(kv ...interface{}) {
+		logger.Log(1, WarningLevel, kv...)
+	}
+	warn("a", 1)
+	require.Regexp(`source=\S+/pkg/slog_test.go:132 `, buf.String())
+}
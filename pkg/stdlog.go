@@ -0,0 +1,64 @@
+package pkg
+
+import (
+	stdlog "log"
+	"regexp"
+	"strconv"
+)
+
+// stdCaller matches the file and line written by the Llongfile flag.
+var stdCaller = regexp.MustCompile(`^(.+?\.go):(\d+): `)
+
+// stdWriter turns the lines of a standard library logger into records.
+type stdWriter struct {
+	logger *Logger
+	level  uint32
+}
+
+// StdLogger returns a standard library logger writing records at level
+// through a, with the caller parsed from the log line.
+func (a *Logger) StdLogger(level uint32) *stdlog.Logger {
+	return stdlog.New(&stdWriter{logger: a, level: level}, "", stdlog.Llongfile)
+}
+
+// RedirectStdLog makes the standard library's default logger, used by
+// log.Printf and friends, write records at level through logger. The
+// returned function restores its previous output, flags and prefix.
+func RedirectStdLog(logger *Logger, level uint32) (restore func()) {
+	out, flags, prefix := stdlog.Writer(), stdlog.Flags(), stdlog.Prefix()
+	stdlog.SetOutput(&stdWriter{logger: logger, level: level})
+	stdlog.SetFlags(stdlog.Llongfile)
+	stdlog.SetPrefix("")
+	return func() {
+		stdlog.SetOutput(out)
+		stdlog.SetFlags(flags)
+		stdlog.SetPrefix(prefix)
+	}
+}
+
+func (w *stdWriter) Write(p []byte) (int, error) {
+	a := w.logger
+	line := string(p)
+	if l := len(line); l > 0 && line[l-1] == '\n' {
+		line = line[:l-1]
+	}
+	var path string
+	var lineno int
+	if loc := stdCaller.FindStringSubmatchIndex(line); loc != nil {
+		path = line[loc[2]:loc[3]]
+		lineno, _ = strconv.Atoi(line[loc[4]:loc[5]])
+		line = line[loc[1]:]
+	}
+	if !a.checkLevelFile(w.level, path) {
+		return len(p), nil
+	}
+	var v interface{} = line
+	if a.enableJSON {
+		m := log{message: line}
+		if a.enableFileLine && path != "" {
+			m[file] = formatCaller(path, lineno)
+		}
+		v = m
+	}
+	return len(p), a.Output(a.now(), w.level, v)
+}
@@ -0,0 +1,72 @@
+package pkg
+
+import (
+	"bytes"
+	stdlog "log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdLogger(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{
+		EnableJSON:     true,
+		EnableFileLine: true,
+	})
+	std := logger.StdLogger(WarningLevel)
+	std.Printf("disk %d%% full", 90)
+	require.Contains(buf.String(), `WARNING {"file":"logger/pkg/stdlog_test.go:20","message":"disk 90% full"}`)
+	buf.Reset()
+
+	std.SetFlags(stdlog.Ldate)
+	std.Print("no caller\n")
+	require.Regexp(`WARNING {"message":"\d{4}/\d\d/\d\d no caller"}\n$`, buf.String())
+	buf.Reset()
+
+	logger.StdLogger(DebugLevel).Print("hidden")
+	require.Empty(buf.String())
+	require.Nil(logger.SetVModule("pkg/stdlog_test.go=debug"))
+	logger.StdLogger(DebugLevel).Print("shown")
+	require.Contains(buf.String(), "DEBUG")
+	buf.Reset()
+	require.Nil(logger.SetVModule("other.go=debug"))
+	logger.StdLogger(DebugLevel).Print("hidden")
+	require.Empty(buf.String())
+
+	logger = New(buf)
+	logger.StdLogger(InfoLevel).Println("plain", "text")
+	require.Contains(buf.String(), `INFO {"message":"plain text"}`)
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{
+		EnableJSON:     true,
+		EnableFileLine: true,
+	})
+	stdlog.SetPrefix("app: ")
+	restore := RedirectStdLog(logger, ErrLevel)
+	stdlog.Println("failed")
+	require.Contains(buf.String(), `ERR {"file":"logger/pkg/stdlog_test.go:53","message":"failed"}`)
+
+	restore()
+	require.Equal(os.Stderr, stdlog.Writer())
+	require.Equal(stdlog.LstdFlags, stdlog.Flags())
+	require.Equal("app: ", stdlog.Prefix())
+	stdlog.SetPrefix("")
+}
+
+func TestStdLoggerMessageWithCaller(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{
+		EnableJSON:     true,
+		EnableFileLine: true,
+	})
+	logger.StdLogger(InfoLevel).Printf("open config.go:12: bad")
+	require.Regexp(`INFO {"file":"logger/pkg/stdlog_test.go:\d+","message":"open config.go:12: bad"}\n$`, buf.String())
+}
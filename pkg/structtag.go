@@ -0,0 +1,229 @@
+package pkg
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Structs with at least one field tagged `log`, or holding such structs in
+// their fields, are written as objects following the tags, instead of with
+// encoding/json:
+//
+//	type User struct {
+//		ID       int    `log:"id"`
+//		Email    string `log:"email,omitempty"`
+//		Password string `log:"-"`
+//		Card     string `log:"redact"`
+//		Phone    string `log:"phone,hash"`
+//		Name     string // as encoding/json would: json tag name or Name
+//	}
+//
+// "-" leaves the field out, omitempty leaves it out if empty as with
+// encoding/json, redact masks the value and hash replaces it with its
+// SHA-256, both using the settings of SetRedaction. redact and hash are
+// options, not names. Embedded structs without a name are inlined.
+
+const (
+	fieldPlain uint8 = iota
+	fieldRedact
+	fieldHash
+)
+
+type fieldPlan struct {
+	index     []int
+	name      string
+	omitEmpty bool
+	mode      uint8
+}
+
+// structPlans caches the *structPlan of struct types, nil for the types
+// without log tags.
+var structPlans sync.Map
+
+type structPlan struct {
+	fields []fieldPlan
+}
+
+func planOf(t reflect.Type) *structPlan {
+	if v, ok := structPlans.Load(t); ok {
+		return v.(*structPlan)
+	}
+	var p *structPlan
+	if hasLogTag(t, map[reflect.Type]bool{}) {
+		p = &structPlan{fields: planFields(t, nil)}
+	}
+	v, _ := structPlans.LoadOrStore(t, p)
+	return v.(*structPlan)
+}
+
+func hasLogTag(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if _, ok := f.Tag.Lookup("log"); ok {
+			return true
+		}
+		if (f.PkgPath == "" || f.Anonymous) && containsLogTag(f.Type, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsLogTag reports whether t is, or holds through pointers, slices,
+// arrays and map values, a struct with log tags.
+func containsLogTag(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			if seen[t] || t.Kind() == reflect.Map && !textKey(t.Key()) {
+				return false
+			}
+			seen[t] = true
+			t = t.Elem()
+		case reflect.Struct:
+			return hasLogTag(t, seen)
+		default:
+			return false
+		}
+	}
+}
+
+func planFields(t reflect.Type, index []int) []fieldPlan {
+	var fields []fieldPlan
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, tagged := f.Tag.Lookup("log")
+		if tag == "-" {
+			continue
+		}
+		fi := append(append([]int(nil), index...), i)
+		name, opts := parseLogTag(tag)
+		if !tagged {
+			jsonTag := f.Tag.Get("json")
+			if jsonTag == "-" {
+				continue
+			}
+			parts := strings.Split(jsonTag, ",")
+			name, opts = parts[0], parts[1:]
+		}
+		ft := indirectType(f.Type)
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			// the fields of unexported embedded structs cannot be read, and
+			// the depth limit stops embedding cycles through pointers.
+			if f.PkgPath == "" && len(index) < 8 {
+				fields = append(fields, planFields(ft, fi)...)
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fp := fieldPlan{index: fi, name: name}
+		for _, o := range opts {
+			switch o {
+			case "omitempty":
+				fp.omitEmpty = true
+			case "redact":
+				if tagged {
+					fp.mode = fieldRedact
+				}
+			case "hash":
+				if tagged {
+					fp.mode = fieldHash
+				}
+			}
+		}
+		fields = append(fields, fp)
+	}
+	return fields
+}
+
+func parseLogTag(tag string) (name string, opts []string) {
+	parts := strings.Split(tag, ",")
+	name, opts = parts[0], parts[1:]
+	if name == "redact" || name == "hash" || name == "omitempty" {
+		return "", parts
+	}
+	return name, opts
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+func (p *structPlan) encode(rv reflect.Value, r *redactor, depth int) map[string]interface{} {
+	m := make(map[string]interface{}, len(p.fields))
+	for _, f := range p.fields {
+		fv, ok := fieldByIndex(rv, f.index)
+		if !ok || f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		v := fv.Interface()
+		switch f.mode {
+		case fieldRedact:
+			if r != nil {
+				v = r.mask
+			} else {
+				v = defaultMask
+			}
+		case fieldHash:
+			salt := ""
+			if r != nil {
+				salt = r.salt
+			}
+			v = hashValue(salt, hashInput(v))
+		default:
+			if nv, ok := encodeValue(v, r, depth+1); ok {
+				v = nv
+			}
+		}
+		m[f.name] = v
+	}
+	return m
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex returning false for fields of
+// nil embedded pointers.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// isEmptyValue reports whether v is empty in the sense of encoding/json's
+// omitempty.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package pkg
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type TagBase struct {
+	Tenant string `log:"tenant"`
+}
+
+type TagAddress struct {
+	City string `log:"city"`
+	Zip  string `log:"-"`
+}
+
+type tagUser struct {
+	*TagBase
+	TagAddress `log:"address"`
+	ID         int       `log:"id"`
+	Email      string    `log:"email,omitempty"`
+	Password   string    `log:"-"`
+	Card       string    `log:"redact"`
+	Phone      string    `log:"phone,hash"`
+	Name       string    `json:"full_name"`
+	Internal   string    `json:"-"`
+	Friends    []tagUser `log:"friends,omitempty"`
+	secret     string
+}
+
+type untagged struct {
+	Password string `json:"password"`
+}
+
+func TestStructTags(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{EnableJSON: true})
+
+	u := &tagUser{
+		TagBase:    &TagBase{Tenant: "acme"},
+		TagAddress: TagAddress{City: "Oslo", Zip: "0150"},
+		ID:         1, Password: "hunter2", Card: "4111", Phone: "555", Name: "Ann", Internal: "x",
+		Friends: []tagUser{{ID: 2}},
+		secret:  "s",
+	}
+	logger.Info("user", u, "plain", untagged{Password: "p"})
+	require.Contains(buf.String(), `{"plain":{"password":"p"},"user":{"Card":"[REDACTED]","address":{"city":"Oslo"},"friends":[{"Card":"[REDACTED]","address":{"city":""},"full_name":"","id":2,"phone":"`+hashValue("", "")+`"}],"full_name":"Ann","id":1,"phone":"`+hashValue("", "555")+`","tenant":"acme"}}`)
+	buf.Reset()
+
+	require.Nil(logger.SetRedaction(&Redaction{Mask: "***", Hash: true, Salt: "pepper"}))
+	logger.Info(map[string]interface{}{"users": []interface{}{tagUser{ID: 3, Card: "1", Phone: "2"}}})
+	require.Contains(buf.String(), `{"users":[{"Card":"***","address":{"city":""},"full_name":"","id":3,"phone":"`+hashValue("pepper", "2")+`"}]}`)
+	buf.Reset()
+
+	text := New(buf)
+	text.Info("user ", TagAddress{City: "Oslo", Zip: "0150"})
+	require.Contains(buf.String(), `{"message":"user map[city:Oslo]"}`)
+
+	require.Nil(planOf(reflect.TypeOf(untagged{})))
+	p := planOf(reflect.TypeOf(tagUser{}))
+	require.Equal(p, planOf(reflect.TypeOf(tagUser{})))
+	require.Equal(8, len(p.fields))
+}
+
+type tagSecret struct {
+	Name     string `log:"name"`
+	Password string `log:"-"`
+}
+
+type tagRequest struct {
+	User   tagSecret
+	Admins []*tagSecret `json:"admins"`
+	ByID   map[string]tagSecret
+	Tries  int `json:"tries"`
+}
+
+type tagRecursive []tagRecursive
+
+func TestStructTagsNested(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{EnableJSON: true})
+
+	req := tagRequest{
+		User:   tagSecret{Name: "ann", Password: "p1"},
+		Admins: []*tagSecret{{Name: "bob", Password: "p2"}},
+		ByID:   map[string]tagSecret{"3": {Name: "cy", Password: "p3"}},
+		Tries:  2,
+	}
+	logger.Info("req", req, "users", map[string]tagSecret{"4": {Name: "di", Password: "p4"}})
+	require.Contains(buf.String(), `{"req":{"ByID":{"3":{"name":"cy"}},"User":{"name":"ann"},"admins":[{"name":"bob"}],"tries":2},"users":{"4":{"name":"di"}}}`)
+	require.NotContains(buf.String(), `"p`)
+	buf.Reset()
+
+	logger.Info("ids", map[int]tagSecret{5: {Name: "ed", Password: "p5"}}, "r", tagRecursive{{}})
+	require.Contains(buf.String(), `{"ids":{"5":{"name":"ed"}},"r":[[]]}`)
+	require.False(encodedType(reflect.TypeOf(tagRecursive{})))
+}
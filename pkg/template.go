@@ -0,0 +1,173 @@
+package pkg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// templateKey is the key of the template of the records written by the *t
+// methods.
+const templateKey = "template"
+
+// maxTemplates bounds the number of parsed templates cached.
+const maxTemplates = 1024
+
+// messageTemplate is a parsed message template such as
+// "user {User} paid {Amount}": the literal text around its holes, one
+// more than the names of the holes.
+type messageTemplate struct {
+	text  []string
+	names []string
+}
+
+var (
+	templates      sync.Map
+	templatesCount int64
+)
+
+// parseTemplate parses s, "{{" and "}}" are literal braces and a brace
+// which does not enclose a name of letters, digits, '_' and '.' is kept.
+func parseTemplate(s string) *messageTemplate {
+	if t, ok := templates.Load(s); ok {
+		return t.(*messageTemplate)
+	}
+	t := &messageTemplate{}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c == '{' || c == '}') && i+1 < len(s) && s[i+1] == c {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+		if c == '{' {
+			if j := strings.IndexByte(s[i+1:], '}'); j > 0 && isTemplateName(s[i+1:i+1+j]) {
+				t.text = append(t.text, b.String())
+				t.names = append(t.names, s[i+1:i+1+j])
+				b.Reset()
+				i += j + 1
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	t.text = append(t.text, b.String())
+	if atomic.AddInt64(&templatesCount, 1) <= maxTemplates {
+		templates.Store(s, t)
+	}
+	return t
+}
+
+func isTemplateName(s string) bool {
+	for _, r := range s {
+		if r != '_' && r != '.' && !('a' <= r && r <= 'z') && !('A' <= r && r <= 'Z') && !('0' <= r && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// render returns the template with its holes replaced by args in order,
+// the holes without an argument are kept. LogValuers must be resolved.
+func (t *messageTemplate) render(args []interface{}) string {
+	var b strings.Builder
+	for i, name := range t.names {
+		b.WriteString(t.text[i])
+		if i >= len(args) {
+			b.WriteString("{" + name + "}")
+			continue
+		}
+		fmt.Fprint(&b, args[i])
+	}
+	b.WriteString(t.text[len(t.names)])
+	return b.String()
+}
+
+// templateKV returns the key value pairs of a record rendering template
+// with args: the message, the template, and args named after the holes,
+// or arg<N> past the last hole. Loggers without JSON only get the message.
+// LogValuers are resolved once, for both the message and the fields.
+func (a *Logger) templateKV(template string, args []interface{}) []interface{} {
+	t := parseTemplate(template)
+	resolved := make([]interface{}, len(args))
+	for i, v := range args {
+		if lv, ok := v.(LogValuer); ok {
+			v = lv.LogValue()
+		}
+		resolved[i] = v
+	}
+	args = resolved
+	msg := t.render(args)
+	if !a.enableJSON {
+		return []interface{}{msg}
+	}
+	kv := make([]interface{}, 0, 2*len(args)+4)
+	for i, v := range args {
+		name := "arg" + strconv.Itoa(i)
+		if i < len(t.names) {
+			name = t.names[i]
+		}
+		kv = append(kv, name, v)
+	}
+	return append(kv, message, msg, templateKey, template)
+}
+
+// Debugt writes a DEBUG record of the message template with args, such as
+// Debugt("user {User} paid {Amount}", user, amount).
+func (a *Logger) Debugt(template string, args ...interface{}) {
+	if a.checkLogLevel(DebugLevel) {
+		a.Output(a.now(), DebugLevel, a.magic(a.templateKV(template, args)...))
+	}
+}
+
+// Infot ...
+func (a *Logger) Infot(template string, args ...interface{}) {
+	if a.checkLogLevel(InfoLevel) {
+		a.Output(a.now(), InfoLevel, a.magic(a.templateKV(template, args)...))
+	}
+}
+
+// Noticet ...
+func (a *Logger) Noticet(template string, args ...interface{}) {
+	if a.checkLogLevel(NoticeLevel) {
+		a.Output(a.now(), NoticeLevel, a.magic(a.templateKV(template, args)...))
+	}
+}
+
+// Warningt ...
+func (a *Logger) Warningt(template string, args ...interface{}) {
+	if a.checkLogLevel(WarningLevel) {
+		a.Output(a.now(), WarningLevel, a.magic(a.templateKV(template, args)...))
+	}
+}
+
+// Errt ...
+func (a *Logger) Errt(template string, args ...interface{}) {
+	if a.checkLogLevel(ErrLevel) {
+		a.Output(a.now(), ErrLevel, a.magic(a.templateKV(template, args)...))
+	}
+}
+
+// Critt ...
+func (a *Logger) Critt(template string, args ...interface{}) {
+	if a.checkLogLevel(CritiLevel) {
+		a.Output(a.now(), CritiLevel, a.magic(a.templateKV(template, args)...))
+	}
+}
+
+// Alertt ...
+func (a *Logger) Alertt(template string, args ...interface{}) {
+	if a.checkLogLevel(AlertLevel) {
+		a.Output(a.now(), AlertLevel, a.magic(a.templateKV(template, args)...))
+	}
+}
+
+// Emergt ...
+func (a *Logger) Emergt(template string, args ...interface{}) {
+	if a.checkLogLevel(EmergLevel) {
+		a.Output(a.now(), EmergLevel, a.magic(a.templateKV(template, args)...))
+	}
+}
@@ -0,0 +1,50 @@
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate(t *testing.T) {
+	require := require.New(t)
+
+	tpl := parseTemplate("user {User} paid {Amount} {{literal}} {not a name} {}")
+	require.Equal([]string{"User", "Amount"}, tpl.names)
+	require.Equal("user u1 paid 10 {literal} {not a name} {}", tpl.render([]interface{}{"u1", 10}))
+	require.Equal("user u1 paid {Amount} {literal} {not a name} {}", tpl.render([]interface{}{"u1"}))
+	require.Equal("a}b", parseTemplate("a}}b").render(nil))
+
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{EnableJSON: true, Deterministic: true}).SetJSONLog()
+	logger.Infot("user {User} paid {Amount}", "u1", 10)
+	require.Equal(`{"Amount":10,"User":"u1","level":"INFO","message":"user u1 paid 10","template":"user {User} paid {Amount}","timestamp":"2000-01-01T00:00:00Z"}`+"\n", buf.String())
+	buf.Reset()
+
+	logger.Errt("charge {Order} failed", "o1", errors.New("declined"))
+	require.Contains(buf.String(), `"Order":"o1","arg1":"declined","level":"ERR","message":"charge o1 failed"`)
+	buf.Reset()
+
+	logger.Debugt("hidden {X}", 1)
+	require.Empty(buf.String())
+
+	ordered := New(buf, Options{EnableJSON: true, Deterministic: true, Format: "json", FieldOrder: true})
+	ordered.Warningt("disk {Disk} at {Percent}%", "sda", 91)
+	require.Equal(`{"timestamp":"2000-01-01T00:00:00Z","level":"WARNING","message":"disk sda at 91%","Disk":"sda","Percent":91,"template":"disk {Disk} at {Percent}%"}`+"\n", buf.String())
+	buf.Reset()
+
+	New(buf, Options{Deterministic: true}).Noticet("user {User}", "u1")
+	require.Equal(`[2000-01-01T00:00:00Z] NOTICE {"message":"user u1"}`+"\n", buf.String())
+	buf.Reset()
+
+	calls := 0
+	lazy := Lazy(func() interface{} {
+		calls++
+		return calls
+	})
+	logger.Infot("v {V}", lazy)
+	require.Equal(1, calls)
+	require.Contains(buf.String(), `"V":1,"level":"INFO","message":"v 1"`)
+}
@@ -0,0 +1,141 @@
+package pkg
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// vrule overrides the logger level for source files matching pattern.
+type vrule struct {
+	pattern []string
+	level   uint32
+}
+
+// match reports whether the trailing path segments of file match the
+// rule's pattern. A last pattern segment without ".go" is matched against
+// the file name without its extension, so "db/*" and "server" both work.
+func (r vrule) match(file string) bool {
+	segs := strings.Split(file, "/")
+	if len(segs) < len(r.pattern) {
+		return false
+	}
+	segs = segs[len(segs)-len(r.pattern):]
+	last := len(r.pattern) - 1
+	for i, p := range r.pattern {
+		s := segs[i]
+		if i == last && !strings.HasSuffix(p, ".go") {
+			s = strings.TrimSuffix(s, ".go")
+		}
+		if ok, _ := path.Match(p, s); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// vmodule is an immutable set of rules, replaced as a whole by SetVModule.
+type vmodule struct {
+	spec     string
+	rules    []vrule
+	min, max uint32
+	// cache maps a caller PC to the index of its rule, -1 for none.
+	cache sync.Map
+}
+
+// level returns the level for the code at pc, or def if no rule matches.
+func (vm *vmodule) level(pc uintptr, def uint32) uint32 {
+	v, ok := vm.cache.Load(pc)
+	if !ok {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		v = vm.rule(frame.File)
+		vm.cache.Store(pc, v)
+	}
+	if idx := v.(int); idx >= 0 {
+		return vm.rules[idx].level
+	}
+	return def
+}
+
+// fileLevel is level for the path of a source file, without caching.
+func (vm *vmodule) fileLevel(file string, def uint32) uint32 {
+	if idx := vm.rule(file); idx >= 0 {
+		return vm.rules[idx].level
+	}
+	return def
+}
+
+// rule returns the index of the first rule matching file, -1 for none.
+func (vm *vmodule) rule(file string) int {
+	for i, r := range vm.rules {
+		if r.match(file) {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseVModule parses a comma separated list of pattern=level rules, such
+// as "db/*=debug,http/server.go=notice". The first matching rule wins.
+func parseVModule(spec string) (*vmodule, error) {
+	vm := &vmodule{spec: spec, min: DebugLevel, max: EmergLevel}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		i := strings.LastIndexByte(item, '=')
+		if i <= 0 {
+			return nil, fmt.Errorf("vmodule: invalid rule %q, want pattern=level", item)
+		}
+		pattern, name := strings.TrimSpace(item[:i]), strings.TrimSpace(item[i+1:])
+		level, ok := lookupLevel(name)
+		if !ok {
+			return nil, fmt.Errorf("vmodule: invalid level %q in rule %q", name, item)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("vmodule: invalid pattern %q: %v", pattern, err)
+		}
+		vm.rules = append(vm.rules, vrule{pattern: strings.Split(strings.Trim(pattern, "/"), "/"), level: level})
+		if level < vm.min {
+			vm.min = level
+		}
+		if level > vm.max {
+			vm.max = level
+		}
+	}
+	if len(vm.rules) == 0 {
+		return nil, nil
+	}
+	return vm, nil
+}
+
+// ValidateVModule reports whether spec is a valid SetVModule spec.
+func ValidateVModule(spec string) error {
+	_, err := parseVModule(spec)
+	return err
+}
+
+// SetVModule sets per-package or per-file levels from a spec such as
+// "db/*=debug,http/server.go=notice". Patterns match the trailing segments
+// of the caller's file path, levels are names or numbers. Matching rules
+// override the logger level, an empty spec removes all rules. The rules
+// apply to the whole tree of named loggers.
+func (a *Logger) SetVModule(spec string) error {
+	vm, err := parseVModule(spec)
+	if err != nil {
+		return err
+	}
+	a.root.vmodule.Store(vm)
+	return nil
+}
+
+// VModule returns the spec set by SetVModule.
+func (a *Logger) VModule() string {
+	if vm, _ := a.root.vmodule.Load().(*vmodule); vm != nil {
+		return vm.spec
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+package pkg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVModule(t *testing.T) {
+	require := require.New(t)
+	buf := new(bytes.Buffer)
+	logger := New(buf, Options{
+		EnableJSON:     true,
+		EnableFileLine: true,
+	})
+	require.Nil(logger.SetVModule("pkg/vmodule_test.go=debug"))
+	require.Equal("pkg/vmodule_test.go=debug", logger.VModule())
+	logger.Debug("a", 1)
+	require.Contains(buf.String(), `DEBUG {"a":1,"file":"logger/pkg/vmodule_test.go:19"}`)
+	buf.Reset()
+
+	require.Nil(logger.SetVModule("db/*=debug, vmodule_test=err"))
+	logger.Debug("a", 1)
+	logger.Info("a", 1)
+	require.Empty(buf.String())
+	logger.Err("a", 1)
+	require.Contains(buf.String(), "ERR")
+	buf.Reset()
+
+	require.Nil(logger.SetVModule("other/*=7,pkg/*=notice"))
+	logger.Info("a", 1)
+	logger.FirstN(1).Info("a", 1)
+	require.Empty(buf.String())
+
+	require.Nil(logger.SetVModule(""))
+	require.Equal("", logger.VModule())
+	logger.Info("a", 1)
+	require.Contains(buf.String(), "INFO")
+
+	require.NotNil(logger.SetVModule("db/*"))
+	require.NotNil(logger.SetVModule("db/*=verbose"))
+	require.NotNil(logger.SetVModule("db/*=8"))
+	require.NotNil(logger.SetVModule("db/[=debug"))
+}
+
+func TestVRule(t *testing.T) {
+	require := require.New(t)
+	vm, err := parseVModule("db/*=debug,http/server.go=notice,client=err")
+	require.Nil(err)
+	require.Equal(ErrLevel, vm.min)
+	require.Equal(DebugLevel, vm.max)
+	require.True(vm.rules[0].match("/src/app/db/query.go"))
+	require.False(vm.rules[0].match("/src/app/db/sub/query.go"))
+	require.True(vm.rules[1].match("/src/app/http/server.go"))
+	require.False(vm.rules[1].match("/src/app/rpc/server.go"))
+	require.True(vm.rules[2].match("/src/app/http/client.go"))
+	require.False(vm.rules[2].match("client.go/x.go"))
+
+	vm, err = parseVModule(" , ")
+	require.Nil(err)
+	require.Nil(vm)
+}
+
+func TestValidateVModule(t *testing.T) {
+	require := require.New(t)
+	require.Nil(ValidateVModule("db/*=debug,http/server.go=notice"))
+	require.Nil(ValidateVModule(""))
+	require.EqualError(ValidateVModule("db/*"), `vmodule: invalid rule "db/*", want pattern=level`)
+	require.NotNil(ValidateVModule("db/[=debug"))
+}